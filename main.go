@@ -3,15 +3,25 @@ package main
 import (
 	"crypto/rand"
 	"encoding/base64"
+	"api/src/banco"
 	"api/src/config"
+	"api/src/logs"
+	"api/src/metricas"
+	"api/src/migracoes"
+	"api/src/repositorios"
 	"api/src/router"
+	"api/src/saude"
 	"fmt"
 	"log"
-	"net/http"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func init() {
 	config.Carregar()
+	logs.Configurar()
 	// Somente para criar a secret key a ser utilizada no package autorizacao, na geração de token
 	if config.RunInit {
 		chave := make([]byte, 64)
@@ -25,12 +35,106 @@ func init() {
 }
 
 func main() {
+	// Subcomando de migrações: api migrate up|down|status
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		executarMigracoes(os.Args[2:])
+		return
+	}
 
 	host := config.Host
 	portaApi := config.Porta
 
-	fmt.Println("Rodando API na porta",host, portaApi)
-	
-	r := router.Gerar()
-	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", portaApi), r))
+	// Pool de conexões único, compartilhado por todas as requisições
+	db, erro := banco.Conectar()
+	if erro != nil {
+		log.Fatal(erro)
+	}
+	defer db.Close()
+
+	pendentes, erro := migracoes.Pendentes(db)
+	if erro != nil {
+		log.Fatal(erro)
+	}
+	if len(pendentes) > 0 {
+		log.Fatalf("existem %d migrações pendentes; execute `api migrate up` antes de iniciar a API", len(pendentes))
+	}
+
+	metricas.RegistrarBanco(db)
+
+	r, controller := router.Gerar(repositorios.NovosRepositorios(db))
+	servidor := router.NovoServidor(fmt.Sprintf(":%d", portaApi), r)
+
+	errosServidor := make(chan error, 1)
+	go func() {
+		errosServidor <- servidor.ListenAndServe()
+	}()
+
+	slog.Info("Rodando API", slog.String("host", host), slog.Int("porta", portaApi))
+	saude.MarcarPronto()
+
+	sinais := make(chan os.Signal, 1)
+	signal.Notify(sinais, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case erro := <-errosServidor:
+		slog.Error("servidor HTTP interrompido", slog.String("erro", erro.Error()))
+		db.Close()
+		os.Exit(1)
+	case sinal := <-sinais:
+		slog.Info("encerrando API", slog.String("sinal", sinal.String()))
+	}
+
+	// O pool do banco é fechado pelo defer, depois dos envios, que ainda gravam tokens
+	router.Encerrar(servidor, controller)
+}
+
+// executarMigracoes trata o subcomando migrate, aplicando, revertendo ou listando as migrações do banco
+func executarMigracoes(argumentos []string) {
+	if len(argumentos) != 1 {
+		log.Fatal("uso: api migrate up|down|status")
+	}
+
+	db, erro := banco.Conectar()
+	if erro != nil {
+		log.Fatal(erro)
+	}
+	defer db.Close()
+
+	switch argumentos[0] {
+	case "up":
+		aplicadas, erro := migracoes.Aplicar(db)
+		for _, migracao := range aplicadas {
+			fmt.Printf("aplicada %04d_%s\n", migracao.Versao, migracao.Nome)
+		}
+		if erro != nil {
+			log.Fatal(erro)
+		}
+		if len(aplicadas) == 0 {
+			fmt.Println("nenhuma migração pendente")
+		}
+	case "down":
+		revertida, erro := migracoes.Reverter(db)
+		if erro != nil {
+			log.Fatal(erro)
+		}
+		if revertida == nil {
+			fmt.Println("nenhuma migração a reverter")
+			return
+		}
+		fmt.Printf("revertida %04d_%s\n", revertida.Versao, revertida.Nome)
+	case "status":
+		situacoes, erro := migracoes.Consultar(db)
+		if erro != nil {
+			log.Fatal(erro)
+		}
+		for _, situacao := range situacoes {
+			estado := "pendente"
+			if situacao.AplicadaEm != nil {
+				estado = "aplicada em " + situacao.AplicadaEm.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%s\t%s\n", situacao.Versao, situacao.Nome, estado)
+		}
+	default:
+		log.Fatal("uso: api migrate up|down|status")
+	}
 }
@@ -1,39 +0,0 @@
--- THIS FILE IS A COPY OF ../sql/sql.sql FILE
--- CREATE DATABASE IF NOT EXISTS devbook;
--- USE devbook;
-
-DROP TABLE IF EXISTS publicacoes;
-
-DROP TABLE IF EXISTS seguidores;
-
-DROP TABLE IF EXISTS usuarios;
-
-CREATE TABLE IF NOT EXISTS usuarios (
-    id int auto_increment primary key,
-    nome varchar(50) not null,
-    nick varchar(50) not null unique,
-    email varchar(100) not null unique,
-    senha varchar(255) not null,
-    criadoEm timestamp default current_timestamp()
-) ENGINE=INNODB;
-
-DROP TABLE IF EXISTS seguidores;
-
-CREATE TABLE IF NOT EXISTS seguidores (
-    usuario_id int not null,
-    FOREIGN KEY (usuario_id) REFERENCES usuarios(id) ON DELETE CASCADE,
-    seguidor_id int not null,
-    FOREIGN KEY (seguidor_id) REFERENCES usuarios(id) ON DELETE CASCADE,
-    PRIMARY KEY(usuario_id, seguidor_id)
-) ENGINE=INNODB;
-
-CREATE TABLE IF NOT EXISTS publicacoes (
-    id int auto_increment primary key,
-    titulo varchar(50) not null,
-    conteudo varchar(500) not null,
-    autor_id int not null,
-    FOREIGN KEY(autor_id) REFERENCES usuarios(id) ON DELETE CASCADE,
-    curtidas int default 0,
-    criadaEm timestamp default current_timestamp
-) ENGINE=INNODB;
-
@@ -0,0 +1,56 @@
+package autenticacao
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// chavePermissoes é a chave das permissões no contexto da requisição.
+// Tipo não exportado para evitar colisão com chaves de outros pacotes.
+type chavePermissoes struct{}
+
+// ComPermissoes retorna uma cópia do contexto contendo as permissões do token validado
+func ComPermissoes(ctx context.Context, permissoes Permissoes) context.Context {
+	return context.WithValue(ctx, chavePermissoes{}, permissoes)
+}
+
+// ExtrairPermissoes retorna as permissões guardadas no contexto da requisição pelo middleware de autenticação
+func ExtrairPermissoes(r *http.Request) (Permissoes, error) {
+	permissoes, ok := r.Context().Value(chavePermissoes{}).(Permissoes)
+	if !ok {
+		return Permissoes{}, errors.New("requisição não autenticada")
+	}
+
+	return permissoes, nil
+}
+
+// ExtrairUsuarioId retorna o id do usuário autenticado na requisição
+func ExtrairUsuarioId(r *http.Request) (uint64, error) {
+	permissoes, erro := ExtrairPermissoes(r)
+	if erro != nil {
+		return 0, erro
+	}
+
+	return permissoes.UsuarioId, nil
+}
+
+// ExtrairJti retorna o identificador (jti) do token de acesso utilizado na requisição
+func ExtrairJti(r *http.Request) (string, error) {
+	permissoes, erro := ExtrairPermissoes(r)
+	if erro != nil {
+		return "", erro
+	}
+
+	return permissoes.Id, nil
+}
+
+// ExtrairPapel retorna o papel do usuário autenticado na requisição
+func ExtrairPapel(r *http.Request) (string, error) {
+	permissoes, erro := ExtrairPermissoes(r)
+	if erro != nil {
+		return "", erro
+	}
+
+	return permissoes.Papel, nil
+}
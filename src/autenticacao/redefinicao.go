@@ -0,0 +1,17 @@
+package autenticacao
+
+// CriarTokenDeRedefinicao gera o token opaco de redefinição de senha, retornando o valor enviado por email
+// e o hash a ser persistido
+func CriarTokenDeRedefinicao() (string, string, error) {
+	valor, erro := gerarValorAleatorio(32)
+	if erro != nil {
+		return "", "", erro
+	}
+
+	return valor, HashTokenDeRedefinicao(valor), nil
+}
+
+// HashTokenDeRedefinicao calcula o hash (SHA-256) de um token de redefinição de senha
+func HashTokenDeRedefinicao(valor string) string {
+	return HashRefreshToken(valor)
+}
@@ -2,41 +2,98 @@ package autenticacao
 
 import (
 	"api/src/config"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
 	jwt "github.com/dgrijalva/jwt-go" //Alias para jwt
 )
 
-func CriarToken(usuarioId uint64) (string, error) {
-	permissoes := jwt.MapClaims{}
-	permissoes["authorized"] = true
-	permissoes["exp"] = time.Now().Add(time.Hour * 6).Unix()
-	permissoes["usuarioId"] = usuarioId
+// Permissoes são as claims carregadas no token de acesso
+type Permissoes struct {
+	Authorized bool   `json:"authorized"`
+	UsuarioId  uint64 `json:"usuarioId"`
+	Papel      string `json:"papel"`
+	jwt.StandardClaims
+}
+
+// CriarToken gera o token de acesso (JWT) do usuário com seu papel, retornando também seu identificador (jti),
+// utilizado para revogar o token antes da expiração
+func CriarToken(usuarioId uint64, papel string) (string, string, error) {
+	jti, erro := gerarValorAleatorio(16)
+	if erro != nil {
+		return "", "", erro
+	}
+
+	permissoes := Permissoes{
+		Authorized: true,
+		UsuarioId:  usuarioId,
+		Papel:      papel,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(config.DuracaoToken).Unix(),
+			Id:        jti,
+		},
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, permissoes)
-		
-	return token.SignedString(config.SecretKey) //secret que virá do .env
+
+	tokenAssinado, erro := token.SignedString(config.SecretKey) //secret que virá do .env
+	if erro != nil {
+		return "", "", erro
+	}
+
+	return tokenAssinado, jti, nil
+}
+
+// CriarRefreshToken gera um refresh token opaco, retornando o valor entregue ao cliente e o hash a ser persistido
+func CriarRefreshToken() (string, string, error) {
+	valor, erro := gerarValorAleatorio(32)
+	if erro != nil {
+		return "", "", erro
+	}
+
+	return valor, HashRefreshToken(valor), nil
+}
+
+// HashRefreshToken calcula o hash (SHA-256) de um refresh token. Somente o hash é guardado no banco.
+func HashRefreshToken(valor string) string {
+	hash := sha256.Sum256([]byte(valor))
+	return hex.EncodeToString(hash[:])
 }
 
-func ValidarToken(r *http.Request) error {
+func gerarValorAleatorio(tamanho int) (string, error) {
+	bytes := make([]byte, tamanho)
+	if _, erro := rand.Read(bytes); erro != nil {
+		return "", erro
+	}
+
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// ValidarToken valida o token de acesso da requisição e retorna suas permissões.
+// O token é interpretado uma única vez, pelo middleware de autenticação; os handlers
+// obtêm as permissões do contexto da requisição (ver ExtrairUsuarioId).
+func ValidarToken(r *http.Request) (Permissoes, error) {
 	tokenString := extrairToken(r)
 
-	token, erro := jwt.Parse(tokenString, retornarChaveDeVerirficacao)
+	var permissoes Permissoes
+
+	token, erro := jwt.ParseWithClaims(tokenString, &permissoes, retornarChaveDeVerirficacao)
 	if erro != nil {
-		return erro
+		return Permissoes{}, erro
 	}
 
-	if _, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		return nil
+	if !token.Valid || permissoes.UsuarioId == 0 || permissoes.Id == "" {
+		return Permissoes{}, errors.New("token inválido")
 	}
 
-	return errors.New("token inválido")
-
+	return permissoes, nil
 }
 
 func extrairToken(r *http.Request) string {
@@ -58,25 +115,3 @@ func retornarChaveDeVerirficacao(token *jwt.Token) (interface{}, error) {
 
 	return config.SecretKey, nil
 }
-
-func ExtrairUsuarioId(r *http.Request) (uint64, error) {
-	tokenString := extrairToken(r)
-
-	token, erro := jwt.Parse(tokenString, retornarChaveDeVerirficacao)
-	if erro != nil {
-		return 0, erro
-	}
-
-	if permissoes, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		permissaoUsuarioId := fmt.Sprintf("%.0f", permissoes["usuarioId"])
-		usuarioId, erro := strconv.ParseUint(permissaoUsuarioId, 10, 64)
-		if erro != nil {
-			return 0, erro
-		}
-
-		return usuarioId, nil
-	}
-
-	return 0, errors.New("token inválido")
-
-}
\ No newline at end of file
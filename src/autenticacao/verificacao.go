@@ -0,0 +1,57 @@
+package autenticacao
+
+import (
+	"api/src/config"
+	"errors"
+	"strconv"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+// finalidadeVerificacao distingue o token de verificação de email dos demais tokens assinados com a mesma chave
+const finalidadeVerificacao = "verificar-email"
+
+// verificacaoDeEmail são as claims do token de verificação. O usuário vai em Subject (e não em usuarioId)
+// para que o token nunca seja aceito como token de acesso.
+type verificacaoDeEmail struct {
+	Finalidade string `json:"finalidade"`
+	Email      string `json:"email"`
+	jwt.StandardClaims
+}
+
+// CriarTokenDeVerificacao gera o token assinado enviado por email para confirmar o endereço do usuário.
+// O token vale para o email informado: se o usuário trocar de email, os tokens anteriores deixam de valer.
+func CriarTokenDeVerificacao(usuarioId uint64, email string) (string, error) {
+	claims := verificacaoDeEmail{
+		Finalidade: finalidadeVerificacao,
+		Email:      email,
+		StandardClaims: jwt.StandardClaims{
+			Subject:   strconv.FormatUint(usuarioId, 10),
+			ExpiresAt: time.Now().Add(config.DuracaoTokenVerificacao).Unix(),
+		},
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(config.SecretKey)
+}
+
+// ValidarTokenDeVerificacao confere assinatura, validade e finalidade do token, retornando o usuário e o email verificados
+func ValidarTokenDeVerificacao(tokenString string) (uint64, string, error) {
+	var claims verificacaoDeEmail
+
+	token, erro := jwt.ParseWithClaims(tokenString, &claims, retornarChaveDeVerirficacao)
+	if erro != nil {
+		return 0, "", erro
+	}
+
+	if !token.Valid || claims.Finalidade != finalidadeVerificacao || claims.Email == "" {
+		return 0, "", errors.New("token de verificação inválido")
+	}
+
+	usuarioId, erro := strconv.ParseUint(claims.Subject, 10, 64)
+	if erro != nil || usuarioId == 0 {
+		return 0, "", errors.New("token de verificação inválido")
+	}
+
+	return usuarioId, claims.Email, nil
+}
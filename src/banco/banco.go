@@ -6,7 +6,8 @@ import (
 	_ "github.com/go-sql-driver/mysql" //Driver, import implícito
 )
 
-// Conectar abre conexão com base de dados
+// Conectar abre o pool de conexões com a base de dados.
+// O pool deve ser criado uma única vez, na inicialização da API, e compartilhado entre as requisições.
 func Conectar() (*sql.DB, error) {
 
 	db, erro := sql.Open("mysql", config.StringConexaoBanco)
@@ -14,10 +15,14 @@ func Conectar() (*sql.DB, error) {
 		return nil, erro
 	}
 
+	db.SetMaxOpenConns(config.BancoMaxConexoesAbertas)
+	db.SetMaxIdleConns(config.BancoMaxConexoesOciosas)
+	db.SetConnMaxLifetime(config.BancoTempoMaximoConexao)
+
 	if erro = db.Ping(); erro != nil {
 		db.Close()
 		return nil, erro
 	}
 
 	return db, nil
-}
\ No newline at end of file
+}
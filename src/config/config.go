@@ -3,9 +3,11 @@ package config
 import (
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 	"github.com/joho/godotenv"
 )
 
@@ -13,15 +15,110 @@ var (
 	// StringConexaoBanco é a string de conexão com MySQL
 	StringConexaoBanco = ""
 
+	// BancoMaxConexoesAbertas limita a quantidade de conexões abertas no pool
+	BancoMaxConexoesAbertas = 25
+
+	// BancoMaxConexoesOciosas limita a quantidade de conexões ociosas mantidas no pool
+	BancoMaxConexoesOciosas = 25
+
+	// BancoTempoMaximoConexao é o tempo máximo que uma conexão pode ser reutilizada
+	BancoTempoMaximoConexao = 5 * time.Minute
+
 	// Porta onde a API vai estar em execução
 	Porta = 0
 
+	// ServidorTempoLeituraCabecalho limita o tempo para o cliente enviar os cabeçalhos da requisição
+	ServidorTempoLeituraCabecalho = 5 * time.Second
+
+	// ServidorTempoLeitura limita o tempo para ler a requisição inteira, incluindo o corpo
+	ServidorTempoLeitura = 15 * time.Second
+
+	// ServidorTempoEscrita limita o tempo para escrever a resposta
+	ServidorTempoEscrita = 30 * time.Second
+
+	// ServidorTempoOcioso é o tempo máximo que uma conexão keep-alive fica aberta sem requisições
+	ServidorTempoOcioso = 60 * time.Second
+
+	// ServidorEsperaEncerramento é o tempo que a API segue atendendo, já sinalizada como não pronta,
+	// antes de parar de aceitar conexões, para que o orquestrador a retire do balanceamento
+	ServidorEsperaEncerramento = time.Duration(0)
+
+	// ServidorTempoEncerramento é o prazo para concluir as requisições em andamento ao encerrar a API
+	ServidorTempoEncerramento = 20 * time.Second
+
+	// ConfiarEmProxy indica que a API roda atrás de um proxy reverso e que o IP do cliente
+	// deve ser lido de X-Forwarded-For
+	ConfiarEmProxy bool
+
 	// Host configura o loopback do server
 	Host = "http://172.27.55.252"
 
 	// SecretKey é a chave que vai ser usado para assinar o token
 	SecretKey []byte
 
+	// DuracaoToken é o tempo de validade do token de acesso (JWT)
+	DuracaoToken = 6 * time.Hour
+
+	// DuracaoRefreshToken é o tempo de validade do refresh token
+	DuracaoRefreshToken = 30 * 24 * time.Hour
+
+	// SenhaTamanhoMinimo é a quantidade mínima de caracteres de uma senha
+	SenhaTamanhoMinimo = 8
+
+	// SenhaClassesMinimas é a quantidade de tipos de caractere (minúsculas, maiúsculas, números e símbolos)
+	// que uma senha deve combinar
+	SenhaClassesMinimas = 2
+
+	// ExigirEmailVerificado impede o login de contas que ainda não confirmaram o email
+	ExigirEmailVerificado bool
+
+	// DuracaoTokenVerificacao é o tempo de validade do link de verificação de email
+	DuracaoTokenVerificacao = 48 * time.Hour
+
+	// DuracaoTokenRedefinicao é o tempo de validade do link de redefinição de senha
+	DuracaoTokenRedefinicao = time.Hour
+
+	// SMTPHost é o servidor usado para enviar emails. Vazio faz os emails irem para EmailArquivo ou para o log.
+	SMTPHost string
+
+	// SMTPPorta é a porta do servidor SMTP
+	SMTPPorta = 587
+
+	// SMTPUsuario e SMTPSenha autenticam no servidor SMTP (opcionais)
+	SMTPUsuario string
+	SMTPSenha   string
+
+	// EmailRemetente é o endereço usado no From dos emails enviados
+	EmailRemetente = "DevBook <nao-responda@devbook.local>"
+
+	// EmailArquivo recebe os emails quando não há SMTP configurado (desenvolvimento local)
+	EmailArquivo string
+
+	// UrlFrontend é a base dos links enviados por email (ex.: https://devbook.com.br).
+	// Vazio faz os emails trazerem somente o token.
+	UrlFrontend string
+
+	// LoginMaxFalhasPorConta é a quantidade de senhas erradas, dentro da janela, que bloqueia a conta temporariamente
+	LoginMaxFalhasPorConta uint64 = 5
+
+	// LoginMaxFalhasPorIP é a quantidade de logins falhos, dentro da janela, que bloqueia o IP temporariamente
+	LoginMaxFalhasPorIP uint64 = 20
+
+	// LoginJanelaFalhas é o período em que as falhas de login são somadas
+	LoginJanelaFalhas = 15 * time.Minute
+
+	// LoginTempoBloqueio é quanto tempo a conta ou o IP ficam impedidos de tentar o login
+	LoginTempoBloqueio = 15 * time.Minute
+
+	// DenunciasParaOcultar é a quantidade de usuários distintos que, ao denunciar uma publicação, a ocultam dos feeds
+	DenunciasParaOcultar uint64 = 5
+
+	// JanelaHashtagsEmAlta é o período, contado a partir de agora, considerado no ranking de hashtags em alta
+	JanelaHashtagsEmAlta = 24 * time.Hour
+
+	// NivelLog é o nível mínimo dos logs escritos pela aplicação (DEBUG, INFO, WARN ou ERROR)
+	NivelLog = slog.LevelInfo
+
 	// RunInit é a chave (booleana) para executar ou não o init no arquivo main.go
 	RunInit bool
 )
@@ -46,10 +143,118 @@ func Carregar() {
 		os.Getenv("DB_NOME"),
 	)
 
+	if valor, erro := strconv.Atoi(os.Getenv("DB_MAX_CONEXOES_ABERTAS")); erro == nil {
+		BancoMaxConexoesAbertas = valor
+	}
+
+	if valor, erro := strconv.Atoi(os.Getenv("DB_MAX_CONEXOES_OCIOSAS")); erro == nil {
+		BancoMaxConexoesOciosas = valor
+	}
+
+	if valor, erro := time.ParseDuration(os.Getenv("DB_TEMPO_MAXIMO_CONEXAO")); erro == nil {
+		BancoTempoMaximoConexao = valor
+	}
+
+	if valor, erro := time.ParseDuration(os.Getenv("API_TEMPO_LEITURA_CABECALHO")); erro == nil {
+		ServidorTempoLeituraCabecalho = valor
+	}
+
+	if valor, erro := time.ParseDuration(os.Getenv("API_TEMPO_LEITURA")); erro == nil {
+		ServidorTempoLeitura = valor
+	}
+
+	if valor, erro := time.ParseDuration(os.Getenv("API_TEMPO_ESCRITA")); erro == nil {
+		ServidorTempoEscrita = valor
+	}
+
+	if valor, erro := time.ParseDuration(os.Getenv("API_TEMPO_OCIOSO")); erro == nil {
+		ServidorTempoOcioso = valor
+	}
+
+	if valor, erro := time.ParseDuration(os.Getenv("API_ESPERA_ENCERRAMENTO")); erro == nil {
+		ServidorEsperaEncerramento = valor
+	}
+
+	if valor, erro := time.ParseDuration(os.Getenv("API_TEMPO_ENCERRAMENTO")); erro == nil {
+		ServidorTempoEncerramento = valor
+	}
+
 	Host = os.Getenv("API_HOST")
 
 	SecretKey = []byte(os.Getenv("SECRET_KEY"))
 
+	if valor, erro := time.ParseDuration(os.Getenv("TOKEN_DURACAO")); erro == nil {
+		DuracaoToken = valor
+	}
+
+	if valor, erro := time.ParseDuration(os.Getenv("REFRESH_TOKEN_DURACAO")); erro == nil {
+		DuracaoRefreshToken = valor
+	}
+
+	ConfiarEmProxy, _ = strconv.ParseBool(os.Getenv("API_CONFIAR_PROXY"))
+
+	if valor, erro := strconv.Atoi(os.Getenv("SENHA_TAMANHO_MINIMO")); erro == nil && valor > 0 {
+		SenhaTamanhoMinimo = valor
+	}
+
+	if valor, erro := strconv.Atoi(os.Getenv("SENHA_CLASSES_MINIMAS")); erro == nil && valor >= 1 && valor <= 4 {
+		SenhaClassesMinimas = valor
+	}
+
+	ExigirEmailVerificado, _ = strconv.ParseBool(os.Getenv("EXIGIR_EMAIL_VERIFICADO"))
+
+	if valor, erro := time.ParseDuration(os.Getenv("TOKEN_VERIFICACAO_DURACAO")); erro == nil && valor > 0 {
+		DuracaoTokenVerificacao = valor
+	}
+
+	if valor, erro := time.ParseDuration(os.Getenv("TOKEN_REDEFINICAO_DURACAO")); erro == nil && valor > 0 {
+		DuracaoTokenRedefinicao = valor
+	}
+
+	SMTPHost = os.Getenv("SMTP_HOST")
+	SMTPUsuario = os.Getenv("SMTP_USUARIO")
+	SMTPSenha = os.Getenv("SMTP_SENHA")
+	EmailArquivo = os.Getenv("EMAIL_ARQUIVO")
+	UrlFrontend = strings.TrimSuffix(os.Getenv("FRONTEND_URL"), "/")
+
+	if valor, erro := strconv.Atoi(os.Getenv("SMTP_PORTA")); erro == nil {
+		SMTPPorta = valor
+	}
+
+	if valor := os.Getenv("EMAIL_REMETENTE"); valor != "" {
+		EmailRemetente = valor
+	}
+
+	if valor, erro := strconv.ParseUint(os.Getenv("LOGIN_MAX_FALHAS_CONTA"), 10, 64); erro == nil && valor > 0 {
+		LoginMaxFalhasPorConta = valor
+	}
+
+	if valor, erro := strconv.ParseUint(os.Getenv("LOGIN_MAX_FALHAS_IP"), 10, 64); erro == nil && valor > 0 {
+		LoginMaxFalhasPorIP = valor
+	}
+
+	if valor, erro := time.ParseDuration(os.Getenv("LOGIN_JANELA_FALHAS")); erro == nil && valor > 0 {
+		LoginJanelaFalhas = valor
+	}
+
+	if valor, erro := time.ParseDuration(os.Getenv("LOGIN_TEMPO_BLOQUEIO")); erro == nil && valor > 0 {
+		LoginTempoBloqueio = valor
+	}
+
+	if valor, erro := strconv.ParseUint(os.Getenv("DENUNCIAS_PARA_OCULTAR"), 10, 64); erro == nil && valor > 0 {
+		DenunciasParaOcultar = valor
+	}
+
+	if valor, erro := time.ParseDuration(os.Getenv("HASHTAGS_JANELA_EM_ALTA")); erro == nil && valor > 0 {
+		JanelaHashtagsEmAlta = valor
+	}
+
+	if nivel := os.Getenv("LOG_NIVEL"); nivel != "" {
+		if erro = NivelLog.UnmarshalText([]byte(nivel)); erro != nil {
+			log.Fatal(erro)
+		}
+	}
+
 	runInitStr := strings.ToLower(os.Getenv("RUN_INIT"))
 	RunInit = runInitStr == "true"
 }
\ No newline at end of file
@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	"api/src/autenticacao"
+	"api/src/config"
+	"api/src/logs"
+	"api/src/modelos"
+	"api/src/respostas"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// BuscarContas lista todas as contas, com papel e suspensão. Rota exclusiva de administradores.
+func (controller Controller) BuscarContas(w http.ResponseWriter, r *http.Request) {
+	paginacao, erro := modelos.NovaPaginacao(r.URL.Query().Get("limite"), r.URL.Query().Get("cursor"))
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
+		return
+	}
+
+	usuarios, proximoCursor, erro := controller.usuarios.BuscarContas(paginacao)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	respostas.JSON(w, http.StatusOK, respostas.Pagina{Dados: usuarios, ProximoCursor: proximoCursor.Codificar()}, nil)
+}
+
+// SuspenderUsuario suspende a conta do usuário e encerra todas as suas sessões. Rota exclusiva de administradores.
+func (controller Controller) SuspenderUsuario(w http.ResponseWriter, r *http.Request) {
+	administradorId, erro := autenticacao.ExtrairUsuarioId(r)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
+		return
+	}
+
+	parametros := mux.Vars(r)
+	usuarioId, erro := strconv.ParseUint(parametros["usuarioId"], 10, 64)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "usuarioId inválido"))
+		return
+	}
+
+	if usuarioId == administradorId {
+		respostas.ERRO(w, r, http.StatusForbidden, respostas.NovoErro(respostas.CodigoAcessoNegado, "não é possível suspender a própria conta"))
+		return
+	}
+
+	repositorio := controller.usuarios
+
+	usuario, erro := repositorio.BuscarSituacao(usuarioId)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	if usuario.ID == 0 {
+		respostas.ERRO(w, r, http.StatusNotFound, respostas.NovoErro(respostas.CodigoUsuarioNaoEncontrado, "usuário inexistente"))
+		return
+	}
+
+	if erro = repositorio.Suspender(usuarioId); erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	// Nenhum token emitido antes da suspensão continua valendo, nem os de sessões já renovadas
+	if erro = controller.tokens.RevogarSessoesDoUsuario(usuarioId, time.Now().Add(config.DuracaoToken)); erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "usuário suspenso", append(logs.Atributos(r.Context()), slog.Uint64("suspensoId", usuarioId))...)
+	respostas.JSON(w, http.StatusNoContent, nil, nil)
+}
+
+// ReativarUsuario remove a suspensão da conta do usuário. Rota exclusiva de administradores.
+func (controller Controller) ReativarUsuario(w http.ResponseWriter, r *http.Request) {
+	parametros := mux.Vars(r)
+	usuarioId, erro := strconv.ParseUint(parametros["usuarioId"], 10, 64)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "usuarioId inválido"))
+		return
+	}
+
+	repositorio := controller.usuarios
+
+	usuario, erro := repositorio.BuscarSituacao(usuarioId)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	if usuario.ID == 0 {
+		respostas.ERRO(w, r, http.StatusNotFound, respostas.NovoErro(respostas.CodigoUsuarioNaoEncontrado, "usuário inexistente"))
+		return
+	}
+
+	if erro = repositorio.Reativar(usuarioId); erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "usuário reativado", append(logs.Atributos(r.Context()), slog.Uint64("reativadoId", usuarioId))...)
+	respostas.JSON(w, http.StatusNoContent, nil, nil)
+}
+
+// RemoverPublicacaoComoAdministrador remove qualquer publicação, independente do autor. Rota exclusiva de administradores.
+func (controller Controller) RemoverPublicacaoComoAdministrador(w http.ResponseWriter, r *http.Request) {
+	parametros := mux.Vars(r)
+	publicacaoId, erro := strconv.ParseUint(parametros["publicacaoId"], 10, 64)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "publicacaoId inválido"))
+		return
+	}
+
+	repositorio := controller.publicacoes
+
+	publicacao, erro := repositorio.BuscarPorId(publicacaoId)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	if publicacao.ID == 0 {
+		respostas.ERRO(w, r, http.StatusNotFound, respostas.NovoErro(respostas.CodigoPublicacaoNaoEncontrada, "publicação não encontrada"))
+		return
+	}
+
+	if erro = repositorio.RemoverPublicacao(publicacaoId); erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "publicação removida pela moderação", append(logs.Atributos(r.Context()),
+		slog.Uint64("publicacaoId", publicacaoId),
+		slog.Uint64("autorId", publicacao.AuthorId),
+	)...)
+	respostas.JSON(w, http.StatusNoContent, nil, nil)
+}
+
+// administrador indica se o usuário tem o papel de administrador. O papel é lido do banco, e não do token,
+// para que o rebaixamento valha imediatamente.
+func (controller Controller) administrador(usuarioId uint64) (bool, error) {
+	usuario, erro := controller.usuarios.BuscarSituacao(usuarioId)
+	if erro != nil {
+		return false, erro
+	}
+
+	return usuario.Papel == modelos.PapelAdministrador, nil
+}
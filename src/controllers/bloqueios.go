@@ -0,0 +1,187 @@
+package controllers
+
+import (
+	"api/src/autenticacao"
+	"api/src/modelos"
+	"api/src/respostas"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// BloquearUsuario bloqueia um usuário: os dois deixam de se seguir, não podem voltar a se seguir
+// e deixam de ver o perfil e as publicações um do outro
+func (controller Controller) BloquearUsuario(w http.ResponseWriter, r *http.Request) {
+	usuarioId, alvoId, ok := controller.lerAlvoDeRestricao(w, r, "não é possível bloquear a si mesmo")
+	if !ok {
+		return
+	}
+
+	if erro := controller.usuarios.Bloquear(usuarioId, alvoId); erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	respostas.JSON(w, http.StatusNoContent, nil, nil)
+}
+
+// DesbloquearUsuario desfaz o bloqueio feito pelo usuário logado
+func (controller Controller) DesbloquearUsuario(w http.ResponseWriter, r *http.Request) {
+	usuarioId, alvoId, ok := controller.lerAlvoDeRestricao(w, r, "não é possível desbloquear a si mesmo")
+	if !ok {
+		return
+	}
+
+	if erro := controller.usuarios.Desbloquear(usuarioId, alvoId); erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	respostas.JSON(w, http.StatusNoContent, nil, nil)
+}
+
+// BuscarBloqueados retorna os usuários bloqueados pelo usuário logado
+func (controller Controller) BuscarBloqueados(w http.ResponseWriter, r *http.Request) {
+	usuarioId, erro := autenticacao.ExtrairUsuarioId(r)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
+		return
+	}
+
+	paginacao, erro := modelos.NovaPaginacao(r.URL.Query().Get("limite"), r.URL.Query().Get("cursor"))
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
+		return
+	}
+
+	bloqueados, proximoCursor, erro := controller.usuarios.BuscarBloqueados(usuarioId, paginacao)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	respostas.JSON(w, http.StatusOK, respostas.Pagina{Dados: bloqueados, ProximoCursor: proximoCursor.Codificar()}, nil)
+}
+
+// SilenciarUsuario esconde as publicações de um usuário do feed do usuário logado, sem que o silenciado saiba
+func (controller Controller) SilenciarUsuario(w http.ResponseWriter, r *http.Request) {
+	usuarioId, alvoId, ok := controller.lerAlvoDeRestricao(w, r, "não é possível silenciar a si mesmo")
+	if !ok {
+		return
+	}
+
+	if erro := controller.usuarios.Silenciar(usuarioId, alvoId); erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	respostas.JSON(w, http.StatusNoContent, nil, nil)
+}
+
+// DessilenciarUsuario volta a exibir as publicações do usuário no feed do usuário logado
+func (controller Controller) DessilenciarUsuario(w http.ResponseWriter, r *http.Request) {
+	usuarioId, alvoId, ok := controller.lerAlvoDeRestricao(w, r, "não é possível dessilenciar a si mesmo")
+	if !ok {
+		return
+	}
+
+	if erro := controller.usuarios.Dessilenciar(usuarioId, alvoId); erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	respostas.JSON(w, http.StatusNoContent, nil, nil)
+}
+
+// BuscarSilenciados retorna os usuários silenciados pelo usuário logado
+func (controller Controller) BuscarSilenciados(w http.ResponseWriter, r *http.Request) {
+	usuarioId, erro := autenticacao.ExtrairUsuarioId(r)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
+		return
+	}
+
+	paginacao, erro := modelos.NovaPaginacao(r.URL.Query().Get("limite"), r.URL.Query().Get("cursor"))
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
+		return
+	}
+
+	silenciados, proximoCursor, erro := controller.usuarios.BuscarSilenciados(usuarioId, paginacao)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	respostas.JSON(w, http.StatusOK, respostas.Pagina{Dados: silenciados, ProximoCursor: proximoCursor.Codificar()}, nil)
+}
+
+// lerAlvoDeRestricao obtém o usuário logado e o usuário da rota, que deve existir e ser outro usuário.
+// Em caso de falha a resposta de erro já é escrita e ok é falso.
+func (controller Controller) lerAlvoDeRestricao(w http.ResponseWriter, r *http.Request, mensagemMesmoUsuario string) (uint64, uint64, bool) {
+	usuarioId, erro := autenticacao.ExtrairUsuarioId(r)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
+		return 0, 0, false
+	}
+
+	parametros := mux.Vars(r)
+	alvoId, erro := strconv.ParseUint(parametros["usuarioId"], 10, 64)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "usuarioId inválido"))
+		return 0, 0, false
+	}
+
+	if alvoId == usuarioId {
+		respostas.ERRO(w, r, http.StatusForbidden, respostas.NovoErro(respostas.CodigoAcessoNegado, mensagemMesmoUsuario))
+		return 0, 0, false
+	}
+
+	alvo, erro := controller.usuarios.BuscarPorId(alvoId)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return 0, 0, false
+	}
+
+	if alvo.ID == 0 {
+		respostas.ERRO(w, r, http.StatusNotFound, respostas.NovoErro(respostas.CodigoUsuarioNaoEncontrado, "usuário inexistente"))
+		return 0, 0, false
+	}
+
+	return usuarioId, alvoId, true
+}
+
+// perfilVisivel responde 404 quando há bloqueio entre o usuário logado e o usuário consultado,
+// tratando o perfil como inexistente. Retorna falso se a resposta de erro já foi escrita.
+func (controller Controller) perfilVisivel(w http.ResponseWriter, r *http.Request, usuarioLogadoId, usuarioId uint64) bool {
+	bloqueado, erro := controller.usuarios.Bloqueado(usuarioLogadoId, usuarioId)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return false
+	}
+
+	if bloqueado {
+		respostas.ERRO(w, r, http.StatusNotFound, respostas.NovoErro(respostas.CodigoUsuarioNaoEncontrado, "usuário inexistente"))
+		return false
+	}
+
+	return true
+}
+
+// publicacaoSemBloqueio responde 404 quando há bloqueio entre o usuário logado e o autor da publicação,
+// tratando a publicação como inexistente. Retorna falso se a resposta de erro já foi escrita.
+func (controller Controller) publicacaoSemBloqueio(w http.ResponseWriter, r *http.Request, usuarioLogadoId, autorId uint64) bool {
+	bloqueado, erro := controller.usuarios.Bloqueado(usuarioLogadoId, autorId)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return false
+	}
+
+	if bloqueado {
+		respostas.ERRO(w, r, http.StatusNotFound, respostas.NovoErro(respostas.CodigoPublicacaoNaoEncontrada, "publicação não encontrada"))
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,235 @@
+package controllers
+
+import (
+	"api/src/autenticacao"
+	"api/src/config"
+	"api/src/modelos"
+	"api/src/respostas"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// CriarComentario adiciona um comentário a uma publicação
+func (controller Controller) CriarComentario(w http.ResponseWriter, r *http.Request) {
+	usuarioId, erro := autenticacao.ExtrairUsuarioId(r)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
+		return
+	}
+
+	parametros := mux.Vars(r)
+	publicacaoId, erro := strconv.ParseUint(parametros["publicacaoId"], 10, 64)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "publicacaoId inválido"))
+		return
+	}
+
+	publicacao, erro := controller.publicacoes.BuscarPorId(publicacaoId)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	if publicacao.ID == 0 {
+		respostas.ERRO(w, r, http.StatusNotFound, respostas.NovoErro(respostas.CodigoPublicacaoNaoEncontrada, "publicação não encontrada"))
+		return
+	}
+
+	if !controller.publicacaoNaoOculta(w, r, usuarioId, publicacao) {
+		return
+	}
+
+	if !controller.publicacaoSemBloqueio(w, r, usuarioId, publicacao.AuthorId) {
+		return
+	}
+
+	if !controller.conteudoVisivel(w, r, usuarioId, publicacao.AuthorId) {
+		return
+	}
+
+	corpoRequisicao, erro := io.ReadAll(r.Body)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnprocessableEntity, erro)
+		return
+	}
+
+	var comentario modelos.Comentario
+	if erro = json.Unmarshal(corpoRequisicao, &comentario); erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
+		return
+	}
+
+	comentario.PublicacaoId = publicacaoId
+	comentario.AutorId = usuarioId
+
+	if erro = comentario.Preparar(); erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
+		return
+	}
+
+	if _, erro = controller.comentarios.Criar(comentario); erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	host := config.Host
+	portaApi := config.Porta
+
+	headers := map[string]string{
+		"location": fmt.Sprintf("%s:%d/publicacoes/%d/comentarios", host, portaApi, publicacaoId),
+	}
+	respostas.JSON(w, http.StatusCreated, nil, headers)
+}
+
+// BuscarComentarios retorna os comentários de uma publicação
+func (controller Controller) BuscarComentarios(w http.ResponseWriter, r *http.Request) {
+	usuarioLogadoId, erro := autenticacao.ExtrairUsuarioId(r)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
+		return
+	}
+
+	parametros := mux.Vars(r)
+	publicacaoId, erro := strconv.ParseUint(parametros["publicacaoId"], 10, 64)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "publicacaoId inválido"))
+		return
+	}
+
+	publicacao, erro := controller.publicacoes.BuscarPorId(publicacaoId)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	if publicacao.ID == 0 {
+		respostas.ERRO(w, r, http.StatusNotFound, respostas.NovoErro(respostas.CodigoPublicacaoNaoEncontrada, "publicação não encontrada"))
+		return
+	}
+
+	if !controller.publicacaoNaoOculta(w, r, usuarioLogadoId, publicacao) {
+		return
+	}
+
+	if !controller.publicacaoSemBloqueio(w, r, usuarioLogadoId, publicacao.AuthorId) {
+		return
+	}
+
+	if !controller.conteudoVisivel(w, r, usuarioLogadoId, publicacao.AuthorId) {
+		return
+	}
+
+	paginacao, erro := modelos.NovaPaginacao(r.URL.Query().Get("limite"), r.URL.Query().Get("cursor"))
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
+		return
+	}
+
+	comentarios, proximoCursor, erro := controller.comentarios.BuscarPorPublicacao(publicacaoId, paginacao)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	respostas.JSON(w, http.StatusOK, respostas.Pagina{Dados: comentarios, ProximoCursor: proximoCursor.Codificar()}, nil)
+}
+
+// AtualizarComentario altera o conteúdo de um comentário. Somente o autor pode alterá-lo.
+func (controller Controller) AtualizarComentario(w http.ResponseWriter, r *http.Request) {
+	usuarioId, erro := autenticacao.ExtrairUsuarioId(r)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
+		return
+	}
+
+	parametros := mux.Vars(r)
+	comentarioId, erro := strconv.ParseUint(parametros["comentarioId"], 10, 64)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "comentarioId inválido"))
+		return
+	}
+
+	comentarioSalvoNoBanco, erro := controller.comentarios.BuscarPorId(comentarioId)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	if comentarioSalvoNoBanco.ID == 0 {
+		respostas.ERRO(w, r, http.StatusNotFound, respostas.NovoErro(respostas.CodigoComentarioNaoEncontrado, "comentário não encontrado"))
+		return
+	}
+
+	if comentarioSalvoNoBanco.AutorId != usuarioId {
+		respostas.ERRO(w, r, http.StatusForbidden, respostas.NovoErro(respostas.CodigoAcessoNegado, "não foi possível atualizar o comentário"))
+		return
+	}
+
+	corpoRequisicao, erro := io.ReadAll(r.Body)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnprocessableEntity, erro)
+		return
+	}
+
+	var comentario modelos.Comentario
+	if erro = json.Unmarshal(corpoRequisicao, &comentario); erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
+		return
+	}
+
+	if erro = comentario.Preparar(); erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
+		return
+	}
+
+	if erro = controller.comentarios.Atualizar(comentarioId, comentario); erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	respostas.JSON(w, http.StatusNoContent, nil, nil)
+}
+
+// RemoverComentario remove um comentário. Somente o autor pode removê-lo.
+func (controller Controller) RemoverComentario(w http.ResponseWriter, r *http.Request) {
+	usuarioId, erro := autenticacao.ExtrairUsuarioId(r)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
+		return
+	}
+
+	parametros := mux.Vars(r)
+	comentarioId, erro := strconv.ParseUint(parametros["comentarioId"], 10, 64)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "comentarioId inválido"))
+		return
+	}
+
+	comentarioSalvoNoBanco, erro := controller.comentarios.BuscarPorId(comentarioId)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	if comentarioSalvoNoBanco.ID == 0 {
+		respostas.ERRO(w, r, http.StatusNotFound, respostas.NovoErro(respostas.CodigoComentarioNaoEncontrado, "comentário não encontrado"))
+		return
+	}
+
+	if comentarioSalvoNoBanco.AutorId != usuarioId {
+		respostas.ERRO(w, r, http.StatusForbidden, respostas.NovoErro(respostas.CodigoAcessoNegado, "não foi possível remover o comentário"))
+		return
+	}
+
+	if erro = controller.comentarios.Remover(comentarioId); erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	respostas.JSON(w, http.StatusNoContent, nil, nil)
+}
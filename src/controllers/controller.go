@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"api/src/email"
+	"api/src/limitador"
+	"api/src/repositorios"
+	"context"
+	"sync"
+)
+
+// Controller agrupa os recursos da API e os repositórios dos quais eles dependem
+type Controller struct {
+	usuarios    repositorios.RepositorioDeUsuarios
+	publicacoes repositorios.RepositorioDePublicacoes
+	comentarios repositorios.RepositorioDeComentarios
+	tokens      repositorios.RepositorioDeTokens
+	denuncias   repositorios.RepositorioDeDenuncias
+	saude       repositorios.RepositorioDeSaude
+
+	tentativasDeLogin *limitador.TentativasDeLogin
+	mailer            email.Mailer
+
+	// envios acompanha os emails enviados fora das requisições, aguardados no encerramento da API
+	envios *sync.WaitGroup
+}
+
+// NovoController cria um controller a partir dos repositórios informados,
+// que podem ser os do MySQL ou os em memória (testes), do armazenamento usado
+// para limitar as tentativas de login e do mailer que envia os emails da API
+func NovoController(repositorios repositorios.Repositorios, armazenamento limitador.Armazenamento, mailer email.Mailer) *Controller {
+	return &Controller{
+		usuarios:    repositorios.Usuarios,
+		publicacoes: repositorios.Publicacoes,
+		comentarios: repositorios.Comentarios,
+		tokens:      repositorios.Tokens,
+		denuncias:   repositorios.Denuncias,
+		saude:       repositorios.Saude,
+
+		tentativasDeLogin: limitador.NovasTentativasDeLogin(armazenamento),
+		mailer:            mailer,
+
+		envios: &sync.WaitGroup{},
+	}
+}
+
+// emSegundoPlano executa o envio fora da requisição, registrando-o para que o encerramento da API o aguarde
+func (controller Controller) emSegundoPlano(envio func()) {
+	controller.envios.Add(1)
+	go func() {
+		defer controller.envios.Done()
+		envio()
+	}()
+}
+
+// AguardarEnvios espera a conclusão dos envios em segundo plano, até o fim do prazo do contexto
+func (controller Controller) AguardarEnvios(ctx context.Context) error {
+	concluidos := make(chan struct{})
+	go func() {
+		controller.envios.Wait()
+		close(concluidos)
+	}()
+
+	select {
+	case <-concluidos:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,275 @@
+package controllers
+
+import (
+	"api/src/autenticacao"
+	"api/src/config"
+	"api/src/modelos"
+	"api/src/repositorios"
+	"api/src/respostas"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// DenunciarPublicacao registra a denúncia de uma publicação. Ao atingir config.DenunciasParaOcultar
+// denunciantes distintos, a publicação é ocultada dos feeds até a moderação.
+func (controller Controller) DenunciarPublicacao(w http.ResponseWriter, r *http.Request) {
+	usuarioId, erro := autenticacao.ExtrairUsuarioId(r)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
+		return
+	}
+
+	parametros := mux.Vars(r)
+	publicacaoId, erro := strconv.ParseUint(parametros["publicacaoId"], 10, 64)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "publicacaoId inválido"))
+		return
+	}
+
+	publicacao, erro := controller.publicacoes.BuscarPorId(publicacaoId)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	if publicacao.ID == 0 {
+		respostas.ERRO(w, r, http.StatusNotFound, respostas.NovoErro(respostas.CodigoPublicacaoNaoEncontrada, "publicação não encontrada"))
+		return
+	}
+
+	if !controller.publicacaoNaoOculta(w, r, usuarioId, publicacao) {
+		return
+	}
+
+	if publicacao.AuthorId == usuarioId {
+		respostas.ERRO(w, r, http.StatusForbidden, respostas.NovoErro(respostas.CodigoAcessoNegado, "não é possível denunciar a própria publicação"))
+		return
+	}
+
+	denuncia, erro := lerDenuncia(r)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
+		return
+	}
+
+	denuncia.DenuncianteId = usuarioId
+	denuncia.PublicacaoId = &publicacaoId
+
+	if _, erro = controller.denuncias.Criar(denuncia); erro != nil {
+		var erroDeDuplicidade repositorios.ErroDeDuplicidade
+		if errors.As(erro, &erroDeDuplicidade) {
+			respostas.ERRO(w, r, http.StatusConflict, respostas.NovoErro(respostas.CodigoDenunciaDuplicada, "publicação já denunciada"))
+			return
+		}
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	denunciantes, erro := controller.denuncias.ContarDenunciantes(publicacaoId)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	if denunciantes >= config.DenunciasParaOcultar {
+		if erro = controller.publicacoes.Ocultar(publicacaoId); erro != nil {
+			respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+			return
+		}
+	}
+
+	respostas.JSON(w, http.StatusCreated, nil, nil)
+}
+
+// DenunciarUsuario registra a denúncia de um usuário
+func (controller Controller) DenunciarUsuario(w http.ResponseWriter, r *http.Request) {
+	denuncianteId, erro := autenticacao.ExtrairUsuarioId(r)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
+		return
+	}
+
+	parametros := mux.Vars(r)
+	usuarioId, erro := strconv.ParseUint(parametros["usuarioId"], 10, 64)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "usuarioId inválido"))
+		return
+	}
+
+	if usuarioId == denuncianteId {
+		respostas.ERRO(w, r, http.StatusForbidden, respostas.NovoErro(respostas.CodigoAcessoNegado, "não é possível denunciar a si mesmo"))
+		return
+	}
+
+	usuario, erro := controller.usuarios.BuscarPorId(usuarioId)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	if usuario.ID == 0 {
+		respostas.ERRO(w, r, http.StatusNotFound, respostas.NovoErro(respostas.CodigoUsuarioNaoEncontrado, "usuário inexistente"))
+		return
+	}
+
+	denuncia, erro := lerDenuncia(r)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
+		return
+	}
+
+	denuncia.DenuncianteId = denuncianteId
+	denuncia.UsuarioId = &usuarioId
+
+	if _, erro = controller.denuncias.Criar(denuncia); erro != nil {
+		var erroDeDuplicidade repositorios.ErroDeDuplicidade
+		if errors.As(erro, &erroDeDuplicidade) {
+			respostas.ERRO(w, r, http.StatusConflict, respostas.NovoErro(respostas.CodigoDenunciaDuplicada, "usuário já denunciado"))
+			return
+		}
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	respostas.JSON(w, http.StatusCreated, nil, nil)
+}
+
+// BuscarDenuncias retorna a fila de moderação, filtrada pela situação (padrão: pendente). Rota exclusiva de administradores.
+func (controller Controller) BuscarDenuncias(w http.ResponseWriter, r *http.Request) {
+	situacao := r.URL.Query().Get("situacao")
+	if situacao == "" {
+		situacao = modelos.DenunciaPendente
+	}
+
+	if !modelos.SituacaoDeDenunciaValida(situacao) {
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "situacao deve ser pendente, resolvida ou descartada"))
+		return
+	}
+
+	paginacao, erro := modelos.NovaPaginacao(r.URL.Query().Get("limite"), r.URL.Query().Get("cursor"))
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
+		return
+	}
+
+	denuncias, proximoCursor, erro := controller.denuncias.BuscarPorSituacao(situacao, paginacao)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	respostas.JSON(w, http.StatusOK, respostas.Pagina{Dados: denuncias, ProximoCursor: proximoCursor.Codificar()}, nil)
+}
+
+// ResolverDenuncia marca a denúncia como procedente. A publicação denunciada continua oculta, se estiver;
+// sua remoção ou a suspensão do usuário são feitas pelas demais rotas de administração.
+func (controller Controller) ResolverDenuncia(w http.ResponseWriter, r *http.Request) {
+	controller.moderarDenuncia(w, r, modelos.DenunciaResolvida)
+}
+
+// DescartarDenuncia marca a denúncia como improcedente. Se a publicação denunciada deixar de atingir
+// o limite de denúncias, ela volta a aparecer nos feeds.
+func (controller Controller) DescartarDenuncia(w http.ResponseWriter, r *http.Request) {
+	controller.moderarDenuncia(w, r, modelos.DenunciaDescartada)
+}
+
+func (controller Controller) moderarDenuncia(w http.ResponseWriter, r *http.Request, situacao string) {
+	moderadorId, erro := autenticacao.ExtrairUsuarioId(r)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
+		return
+	}
+
+	parametros := mux.Vars(r)
+	denunciaId, erro := strconv.ParseUint(parametros["denunciaId"], 10, 64)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "denunciaId inválido"))
+		return
+	}
+
+	repositorio := controller.denuncias
+
+	denuncia, erro := repositorio.BuscarPorId(denunciaId)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	if denuncia.ID == 0 {
+		respostas.ERRO(w, r, http.StatusNotFound, respostas.NovoErro(respostas.CodigoDenunciaNaoEncontrada, "denúncia não encontrada"))
+		return
+	}
+
+	moderada, erro := repositorio.Moderar(denunciaId, moderadorId, situacao)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	if !moderada {
+		respostas.ERRO(w, r, http.StatusConflict, respostas.NovoErro(respostas.CodigoDenunciaJaModerada, "denúncia já moderada"))
+		return
+	}
+
+	if situacao == modelos.DenunciaDescartada && denuncia.PublicacaoId != nil {
+		denunciantes, erro := repositorio.ContarDenunciantes(*denuncia.PublicacaoId)
+		if erro != nil {
+			respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+			return
+		}
+
+		if denunciantes < config.DenunciasParaOcultar {
+			if erro = controller.publicacoes.Reexibir(*denuncia.PublicacaoId); erro != nil {
+				respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+				return
+			}
+		}
+	}
+
+	respostas.JSON(w, http.StatusNoContent, nil, nil)
+}
+
+// lerDenuncia lê e valida o corpo de uma denúncia (motivo e descrição)
+func lerDenuncia(r *http.Request) (modelos.Denuncia, error) {
+	corpoRequisicao, erro := io.ReadAll(r.Body)
+	if erro != nil {
+		return modelos.Denuncia{}, erro
+	}
+
+	var denuncia modelos.Denuncia
+	if erro = json.Unmarshal(corpoRequisicao, &denuncia); erro != nil {
+		return modelos.Denuncia{}, erro
+	}
+
+	if erro = denuncia.Preparar(); erro != nil {
+		return modelos.Denuncia{}, erro
+	}
+
+	return modelos.Denuncia{Motivo: denuncia.Motivo, Descricao: denuncia.Descricao}, nil
+}
+
+// publicacaoNaoOculta responde 404 quando a publicação foi ocultada pela moderação e o usuário logado não é
+// o autor nem administrador, tratando-a como inexistente. Retorna falso se a resposta de erro já foi escrita.
+func (controller Controller) publicacaoNaoOculta(w http.ResponseWriter, r *http.Request, usuarioLogadoId uint64, publicacao modelos.Publicacao) bool {
+	if !publicacao.Oculta() || publicacao.AuthorId == usuarioLogadoId {
+		return true
+	}
+
+	administrador, erro := controller.administrador(usuarioLogadoId)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return false
+	}
+
+	if !administrador {
+		respostas.ERRO(w, r, http.StatusNotFound, respostas.NovoErro(respostas.CodigoPublicacaoNaoEncontrada, "publicação não encontrada"))
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"api/src/autenticacao"
+	"api/src/config"
+	"api/src/modelos"
+	"api/src/respostas"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// BuscarPublicacoesPorHashtag retorna as publicações que usam a hashtag, das mais recentes para as mais antigas
+func (controller Controller) BuscarPublicacoesPorHashtag(w http.ResponseWriter, r *http.Request) {
+	usuarioLogadoId, erro := autenticacao.ExtrairUsuarioId(r)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
+		return
+	}
+
+	hashtag := modelos.NormalizarHashtag(mux.Vars(r)["tag"])
+	if !modelos.HashtagValida(hashtag) {
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "hashtag inválida"))
+		return
+	}
+
+	paginacao, erro := modelos.NovaPaginacao(r.URL.Query().Get("limite"), r.URL.Query().Get("cursor"))
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
+		return
+	}
+
+	publicacoes, proximoCursor, erro := controller.publicacoes.BuscarPorHashtag(hashtag, usuarioLogadoId, paginacao)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	respostas.JSON(w, http.StatusOK, respostas.Pagina{Dados: publicacoes, ProximoCursor: proximoCursor.Codificar()}, nil)
+}
+
+// BuscarHashtagsEmAlta retorna as hashtags usadas pelo maior número de publicações recentes
+func (controller Controller) BuscarHashtagsEmAlta(w http.ResponseWriter, r *http.Request) {
+	// Somente o limite é usado: o ranking não é paginado
+	paginacao, erro := modelos.NovaPaginacao(r.URL.Query().Get("limite"), "")
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
+		return
+	}
+
+	desde := time.Now().Add(-config.JanelaHashtagsEmAlta)
+
+	hashtags, erro := controller.publicacoes.BuscarHashtagsEmAlta(desde, paginacao.Limite)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	respostas.JSON(w, http.StatusOK, hashtags, nil)
+}
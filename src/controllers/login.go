@@ -2,67 +2,282 @@ package controllers
 
 import (
 	"api/src/autenticacao"
-	"api/src/banco"
+	"api/src/config"
+	"api/src/limitador"
+	"api/src/metricas"
 	"api/src/modelos"
-	"api/src/repositorios"
 	"api/src/respostas"
 	"api/src/seguranca"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 // Login é responsável pela autenticação do usuário
-func Login(w http.ResponseWriter, r *http.Request) {
+func (controller Controller) Login(w http.ResponseWriter, r *http.Request) {
 	corpoDaRequisicao, erro := io.ReadAll(r.Body)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusUnprocessableEntity, erro)
+		respostas.ERRO(w, r, http.StatusUnprocessableEntity, erro)
 		return
 	}
 
 	var usuario modelos.Usuario
 	if erro = json.Unmarshal(corpoDaRequisicao, &usuario); erro != nil {
-		respostas.ERRO(w, http.StatusBadRequest, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
 		return
 	}
 
-	db, erro := banco.Conectar()
+	ip := limitador.IPDoCliente(r)
+	tentativas := controller.tentativasDeLogin
+
+	espera, erro := tentativas.Verificar(ip, usuario.Email)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	if espera > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(espera.Seconds()))))
+		respostas.ERRO(w, r, http.StatusTooManyRequests, respostas.NovoErro(respostas.CodigoLimiteExcedido, "muitas tentativas de login, tente novamente mais tarde"))
 		return
 	}
-	defer db.Close()
 
-	repositorio := repositorios.NovoRepositorioDeUsuarios(db)
+	repositorio := controller.usuarios
 
 	usuarioSalvo, erro := repositorio.BuscarPorEmail(usuario.Email)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	// Email inexistente gasta o mesmo tempo de uma senha errada e recebe a mesma resposta
+	if usuarioSalvo.ID == 0 {
+		seguranca.SimularVerificacao(usuario.Senha)
+		erro = errors.New("usuário inexistente")
+	} else {
+		erro = seguranca.VerificarSenha(usuarioSalvo.Senha, usuario.Senha)
+	}
+
+	if erro != nil {
+		metricas.LoginFalhou()
+		if erro = tentativas.RegistrarFalha(ip, usuario.Email); erro != nil {
+			respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+			return
+		}
+		respostas.ERRO(w, r, http.StatusUnauthorized, respostas.NovoErro(respostas.CodigoCredenciaisInvalidas, "usuario ou senha inválidos"))
+		return
+	}
+
+	if erro = tentativas.RegistrarSucesso(usuario.Email); erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	if usuarioSalvo.Suspenso() {
+		respostas.ERRO(w, r, http.StatusForbidden, respostas.NovoErro(respostas.CodigoUsuarioSuspenso, "conta suspensa"))
+		return
+	}
+
+	if config.ExigirEmailVerificado && !usuarioSalvo.Verificado() {
+		respostas.ERRO(w, r, http.StatusForbidden, respostas.NovoErro(respostas.CodigoEmailNaoVerificado, "email não verificado"))
+		return
+	}
+
+	respostaToken, erro := controller.emitirTokens(usuarioSalvo)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	metricas.LoginRealizado()
+	respostas.JSON(w, http.StatusOK, respostaToken, nil)
+}
+
+// RenovarToken troca um refresh token válido por um novo par de tokens.
+// O refresh token utilizado é revogado (rotação); a reutilização de um token já revogado
+// indica vazamento e encerra todas as sessões do usuário.
+func (controller Controller) RenovarToken(w http.ResponseWriter, r *http.Request) {
+	corpoDaRequisicao, erro := io.ReadAll(r.Body)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnprocessableEntity, erro)
 		return
 	}
 
-	if erro = seguranca.VerificarSenha(usuarioSalvo.Senha, usuario.Senha); erro != nil {
-		respostas.ERRO(w, http.StatusUnauthorized, errors.New("usuario ou senha inválidos"))
+	var requisicao requisicaoRefreshToken
+	if erro = json.Unmarshal(corpoDaRequisicao, &requisicao); erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
 		return
 	}
 
-	token, erro := autenticacao.CriarToken(usuarioSalvo.ID)
+	if requisicao.RefreshToken == "" {
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoDadosInvalidos, "refresh token obrigatório"))
+		return
+	}
+
+	repositorio := controller.tokens
+
+	refreshToken, erro := repositorio.BuscarRefreshToken(autenticacao.HashRefreshToken(requisicao.RefreshToken))
 	if erro != nil {
-		respostas.ERRO(w, http.StatusUnauthorized, errors.New("usuario ou senha inválidos"))
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	if refreshToken.ID == 0 {
+		respostas.ERRO(w, r, http.StatusUnauthorized, respostas.NovoErro(respostas.CodigoTokenInvalido, "refresh token inválido"))
+		return
+	}
+
+	if refreshToken.RevogadoEm != nil {
+		if erro = repositorio.RevogarSessoesDoUsuario(refreshToken.UsuarioId, time.Now().Add(config.DuracaoToken)); erro != nil {
+			respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+			return
+		}
+		respostas.ERRO(w, r, http.StatusUnauthorized, respostas.NovoErro(respostas.CodigoTokenInvalido, "refresh token inválido"))
 		return
 	}
 
-	fmt.Println(usuarioSalvo, token)
+	if !refreshToken.Valido() {
+		respostas.ERRO(w, r, http.StatusUnauthorized, respostas.NovoErro(respostas.CodigoTokenInvalido, "refresh token expirado"))
+		return
+	}
 
-	var respostaToken respostaToken
+	// Papel e suspensão são lidos novamente, pois podem ter mudado desde a emissão do refresh token
+	usuario, erro := controller.usuarios.BuscarSituacao(refreshToken.UsuarioId)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
 
-	respostaToken.Token = token
+	if usuario.ID == 0 {
+		respostas.ERRO(w, r, http.StatusUnauthorized, respostas.NovoErro(respostas.CodigoTokenInvalido, "refresh token inválido"))
+		return
+	}
+
+	if usuario.Suspenso() {
+		respostas.ERRO(w, r, http.StatusForbidden, respostas.NovoErro(respostas.CodigoUsuarioSuspenso, "conta suspensa"))
+		return
+	}
+
+	revogado, erro := repositorio.RevogarRefreshToken(refreshToken.ID)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	// Outra requisição renovou o mesmo token entre a busca e a revogação
+	if !revogado {
+		respostas.ERRO(w, r, http.StatusUnauthorized, respostas.NovoErro(respostas.CodigoTokenInvalido, "refresh token inválido"))
+		return
+	}
+
+	// O token de acesso emitido junto com o refresh token anterior deixa de valer. Sem isso ele escaparia
+	// de RevogarSessoesDoUsuario, que só alcança os tokens de acesso dos refresh tokens ainda ativos.
+	if erro = repositorio.RevogarAcesso(refreshToken.AcessoJti, time.Now().Add(config.DuracaoToken)); erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	respostaToken, erro := controller.emitirTokens(usuario)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
 
 	respostas.JSON(w, http.StatusOK, respostaToken, nil)
 }
 
+// Logout encerra a sessão: revoga o token de acesso da requisição e, se informado, o refresh token
+func (controller Controller) Logout(w http.ResponseWriter, r *http.Request) {
+	usuarioId, erro := autenticacao.ExtrairUsuarioId(r)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
+		return
+	}
+
+	jti, erro := autenticacao.ExtrairJti(r)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
+		return
+	}
+
+	corpoDaRequisicao, erro := io.ReadAll(r.Body)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnprocessableEntity, erro)
+		return
+	}
+
+	var requisicao requisicaoRefreshToken
+	if len(corpoDaRequisicao) > 0 {
+		if erro = json.Unmarshal(corpoDaRequisicao, &requisicao); erro != nil {
+			respostas.ERRO(w, r, http.StatusBadRequest, erro)
+			return
+		}
+	}
+
+	repositorio := controller.tokens
+
+	if requisicao.RefreshToken != "" {
+		refreshToken, erro := repositorio.BuscarRefreshToken(autenticacao.HashRefreshToken(requisicao.RefreshToken))
+		if erro != nil {
+			respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+			return
+		}
+
+		if refreshToken.ID != 0 && refreshToken.UsuarioId == usuarioId {
+			if _, erro = repositorio.RevogarRefreshToken(refreshToken.ID); erro != nil {
+				respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+				return
+			}
+
+			// O refresh token pode ter sido emitido com outro token de acesso, que também é revogado
+			if erro = repositorio.RevogarAcesso(refreshToken.AcessoJti, time.Now().Add(config.DuracaoToken)); erro != nil {
+				respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+				return
+			}
+		}
+	}
+
+	if erro = repositorio.RevogarAcesso(jti, time.Now().Add(config.DuracaoToken)); erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	respostas.JSON(w, http.StatusNoContent, nil, nil)
+}
+
+// emitirTokens gera o token de acesso e o refresh token do usuário, persistindo o hash do refresh token
+func (controller Controller) emitirTokens(usuario modelos.Usuario) (respostaToken, error) {
+	token, jti, erro := autenticacao.CriarToken(usuario.ID, usuario.Papel)
+	if erro != nil {
+		return respostaToken{}, erro
+	}
+
+	refreshToken, refreshTokenHash, erro := autenticacao.CriarRefreshToken()
+	if erro != nil {
+		return respostaToken{}, erro
+	}
+
+	if erro = controller.tokens.CriarRefreshToken(modelos.RefreshToken{
+		UsuarioId: usuario.ID,
+		TokenHash: refreshTokenHash,
+		AcessoJti: jti,
+		ExpiraEm:  time.Now().Add(config.DuracaoRefreshToken),
+	}); erro != nil {
+		return respostaToken{}, erro
+	}
+
+	return respostaToken{Token: token, RefreshToken: refreshToken}, nil
+}
+
 type respostaToken struct {
-	Token string `json:"token"`
-}
\ No newline at end of file
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+}
+
+type requisicaoRefreshToken struct {
+	RefreshToken string `json:"refreshToken"`
+}
@@ -2,13 +2,11 @@ package controllers
 
 import (
 	"api/src/autenticacao"
-	"api/src/banco"
 	"api/src/config"
+	"api/src/metricas"
 	"api/src/modelos"
-	"api/src/repositorios"
 	"api/src/respostas"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -18,45 +16,41 @@ import (
 )
 
 // CriarPublicacao adiciona uma publicação
-func CriarPublicacao(w http.ResponseWriter, r *http.Request) {
+func (controller Controller) CriarPublicacao(w http.ResponseWriter, r *http.Request) {
 	usuarioId, erro := autenticacao.ExtrairUsuarioId(r)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusUnauthorized, erro)
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
 		return
 	}
 
 	corpoRequisicao, erro := io.ReadAll(r.Body)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusBadRequest, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
 		return
 	}
 
 	var publicacao modelos.Publicacao
 
 	if erro = json.Unmarshal(corpoRequisicao, &publicacao); erro != nil {
-		respostas.ERRO(w, http.StatusBadRequest, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
 		return
 	}
 
 	publicacao.AuthorId = usuarioId
 
 	if erro = publicacao.Preparar(); erro != nil {
-		respostas.ERRO(w, http.StatusBadRequest, erro)
-	}
-
-	db, erro := banco.Conectar()
-	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
 		return
 	}
 
-	repositorio := repositorios.NovoRepositorioDePublicacoes(db)
+	repositorio := controller.publicacoes
 
 	publicacaoId, erro := repositorio.Criar(publicacao)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
 		return
 	}
+	metricas.PublicacaoCriada()
 
 	host := config.Host
 	portaApi := config.Porta
@@ -68,55 +62,101 @@ func CriarPublicacao(w http.ResponseWriter, r *http.Request) {
 }
 
 // BuscarPublicacoes retorna todas as publicações
-func BuscarPublicacoes(w http.ResponseWriter, r *http.Request) {
+func (controller Controller) BuscarPublicacoes(w http.ResponseWriter, r *http.Request) {
 	usuarioId, erro := autenticacao.ExtrairUsuarioId(r)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusUnauthorized, erro)
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
+		return
+	}
+
+	paginacao, erro := modelos.NovaPaginacao(r.URL.Query().Get("limite"), r.URL.Query().Get("cursor"))
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
+		return
+	}
+
+	repositorio := controller.publicacoes
+	publicacoes, proximoCursor, erro := repositorio.Buscar(usuarioId, paginacao)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	respostas.JSON(w, http.StatusOK, respostas.Pagina{Dados: publicacoes, ProximoCursor: proximoCursor.Codificar()}, nil)
+}
+
+// PesquisarPublicacoes faz a busca textual nas publicações, das mais relevantes para as menos relevantes.
+// Cada resultado traz um trecho do conteúdo com os termos buscados destacados.
+func (controller Controller) PesquisarPublicacoes(w http.ResponseWriter, r *http.Request) {
+	usuarioLogadoId, erro := autenticacao.ExtrairUsuarioId(r)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
+		return
+	}
+
+	busca, termos, erro := modelos.PrepararBusca(r.URL.Query().Get("q"))
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
 		return
 	}
 
-	db, erro := banco.Conectar()
+	paginacao, erro := modelos.NovaPaginacao(r.URL.Query().Get("limite"), r.URL.Query().Get("cursor"))
 	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
+		return
 	}
-	defer db.Close()
 
-	repositorio := repositorios.NovoRepositorioDePublicacoes(db)
-	publicacoes, erro := repositorio.Buscar(usuarioId)
+	repositorio := controller.publicacoes
+	publicacoes, proximoCursor, erro := repositorio.Pesquisar(busca, usuarioLogadoId, paginacao)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
 		return
 	}
 
-	respostas.JSON(w, http.StatusOK, publicacoes, nil)
+	for i := range publicacoes {
+		publicacoes[i].Trecho = modelos.DestacarTrecho(publicacoes[i].Conteudo, termos)
+	}
+
+	respostas.JSON(w, http.StatusOK, respostas.Pagina{Dados: publicacoes, ProximoCursor: proximoCursor.Codificar()}, nil)
 }
 
 // BuscarPublicacao retorna uma publicação
-func BuscarPublicacao(w http.ResponseWriter, r *http.Request) {
-	parametros := mux.Vars(r)
-	publicacaoId, erro := strconv.ParseUint(parametros["publicacaoId"], 10, 64)
+func (controller Controller) BuscarPublicacao(w http.ResponseWriter, r *http.Request) {
+	usuarioLogadoId, erro := autenticacao.ExtrairUsuarioId(r)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusBadRequest, erro)
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
 		return
 	}
 
-	db, erro := banco.Conectar()
+	parametros := mux.Vars(r)
+	publicacaoId, erro := strconv.ParseUint(parametros["publicacaoId"], 10, 64)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "publicacaoId inválido"))
 		return
 	}
-	defer db.Close()
 
-	repositorio := repositorios.NovoRepositorioDePublicacoes(db)
+	repositorio := controller.publicacoes
 
 	publicacao, erro := repositorio.BuscarPorId(publicacaoId)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
 		return
 	}
 
 	if publicacao.ID == 0 {
-		respostas.ERRO(w, http.StatusNotFound, errors.New("publicação não encontrada"))
+		respostas.ERRO(w, r, http.StatusNotFound, respostas.NovoErro(respostas.CodigoPublicacaoNaoEncontrada, "publicação não encontrada"))
+		return
+	}
+
+	if !controller.publicacaoNaoOculta(w, r, usuarioLogadoId, publicacao) {
+		return
+	}
+
+	if !controller.publicacaoSemBloqueio(w, r, usuarioLogadoId, publicacao.AuthorId) {
+		return
+	}
+
+	if !controller.conteudoVisivel(w, r, usuarioLogadoId, publicacao.AuthorId) {
 		return
 	}
 
@@ -124,60 +164,53 @@ func BuscarPublicacao(w http.ResponseWriter, r *http.Request) {
 }
 
 // AtualizarPublicacao atualiza uma publicação
-func AtualizarPublicacao(w http.ResponseWriter, r *http.Request) {
+func (controller Controller) AtualizarPublicacao(w http.ResponseWriter, r *http.Request) {
 	usuarioId, erro := autenticacao.ExtrairUsuarioId(r)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusUnauthorized, erro)
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
 		return
 	}
 
 	parametros := mux.Vars(r)
 	publicacaoId, erro := strconv.ParseUint(parametros["publicacaoId"], 10, 64)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusBadRequest, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "publicacaoId inválido"))
 		return
 	}
 
-	db, erro := banco.Conectar()
-	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
-		return
-	}
-	defer db.Close()
-
-	repositorio := repositorios.NovoRepositorioDePublicacoes(db)
+	repositorio := controller.publicacoes
 
 	publicacaoSalvaNoBanco, erro := repositorio.BuscarPorId(publicacaoId)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusBadRequest, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
 		return
 	}
 
 	if publicacaoSalvaNoBanco.AuthorId != usuarioId {
-		respostas.ERRO(w, http.StatusForbidden, errors.New("não foi possível atualizar a publicação"))
+		respostas.ERRO(w, r, http.StatusForbidden, respostas.NovoErro(respostas.CodigoAcessoNegado, "não foi possível atualizar a publicação"))
 		return
 	}
 
 	corpoRequisicao, erro := io.ReadAll(r.Body)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusUnprocessableEntity, erro)
+		respostas.ERRO(w, r, http.StatusUnprocessableEntity, erro)
 		return
 	}
 
 	var publicacao modelos.Publicacao
 
 	if erro = json.Unmarshal(corpoRequisicao, &publicacao); erro != nil {
-		respostas.ERRO(w, http.StatusBadRequest, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
 		return
 	}
 
 	if erro = publicacao.Preparar(); erro != nil {
-		respostas.ERRO(w, http.StatusBadRequest, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
 		return
 	}
 
 	if erro = repositorio.Atualizar(publicacaoId, publicacao); erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
 		return
 	}
 
@@ -185,42 +218,35 @@ func AtualizarPublicacao(w http.ResponseWriter, r *http.Request) {
 }
 
 // RemoverPublicacao remove uma publicação
-func RemoverPublicacao(w http.ResponseWriter, r *http.Request) {
+func (controller Controller) RemoverPublicacao(w http.ResponseWriter, r *http.Request) {
 	usuarioId, erro := autenticacao.ExtrairUsuarioId(r)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusUnauthorized, erro)
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
 		return
 	}
 
 	parametros := mux.Vars(r)
 	publicacaoId, erro := strconv.ParseUint(parametros["publicacaoId"], 10, 64)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusBadRequest, erro)
-		return
-	}
-
-	db, erro := banco.Conectar()
-	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "publicacaoId inválido"))
 		return
 	}
-	defer db.Close()
 
-	repositorio := repositorios.NovoRepositorioDePublicacoes(db)
+	repositorio := controller.publicacoes
 
 	publicacaoSalvaNoBanco, erro := repositorio.BuscarPorId(publicacaoId)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusBadRequest, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
 		return
 	}
 
 	if publicacaoSalvaNoBanco.AuthorId != usuarioId {
-		respostas.ERRO(w, http.StatusForbidden, errors.New("não foi possível remover a publicação"))
+		respostas.ERRO(w, r, http.StatusForbidden, respostas.NovoErro(respostas.CodigoAcessoNegado, "não foi possível remover a publicação"))
 		return
 	}
 
 	if erro = repositorio.RemoverPublicacao(publicacaoId); erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
 		return
 	}
 
@@ -228,52 +254,87 @@ func RemoverPublicacao(w http.ResponseWriter, r *http.Request) {
 }
 
 // BuscarPublicacoesPorUsuario traz todas as publicações de um determinado usuário
-func BuscarPublicacoesPorUsuario(w http.ResponseWriter, r *http.Request) {
+func (controller Controller) BuscarPublicacoesPorUsuario(w http.ResponseWriter, r *http.Request) {
+	usuarioLogadoId, erro := autenticacao.ExtrairUsuarioId(r)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
+		return
+	}
+
 	parametros := mux.Vars(r)
 	usuarioId, erro := strconv.ParseUint(parametros["usuarioId"], 10, 64)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusBadRequest, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "usuarioId inválido"))
 		return
 	}
 
-	db, erro := banco.Conectar()
-	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+	if !controller.perfilVisivel(w, r, usuarioLogadoId, usuarioId) {
+		return
+	}
+
+	if !controller.conteudoVisivel(w, r, usuarioLogadoId, usuarioId) {
 		return
 	}
-	defer db.Close()
 
-	repositorio := repositorios.NovoRepositorioDePublicacoes(db)
+	repositorio := controller.publicacoes
 
-	publicacoes, erro := repositorio.BuscarPorUsuario(usuarioId)
+	paginacao, erro := modelos.NovaPaginacao(r.URL.Query().Get("limite"), r.URL.Query().Get("cursor"))
 	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
 		return
 	}
 
-	respostas.JSON(w, http.StatusOK, publicacoes, nil)
+	publicacoes, proximoCursor, erro := repositorio.BuscarPorUsuario(usuarioId, usuarioLogadoId, paginacao)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	respostas.JSON(w, http.StatusOK, respostas.Pagina{Dados: publicacoes, ProximoCursor: proximoCursor.Codificar()}, nil)
 }
 
-// CurtirPublicacao incrementa curtida em 1
-func CurtirPublicacao(w http.ResponseWriter, r *http.Request) {
+// CurtirPublicacao registra a curtida do usuário logado na publicação
+func (controller Controller) CurtirPublicacao(w http.ResponseWriter, r *http.Request) {
+	usuarioId, erro := autenticacao.ExtrairUsuarioId(r)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
+		return
+	}
+
 	parametros := mux.Vars(r)
 	publicacaoId, erro := strconv.ParseUint(parametros["publicacaoId"], 10, 64)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusBadRequest, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "publicacaoId inválido"))
 		return
 	}
 
-	db, erro := banco.Conectar()
+	repositorio := controller.publicacoes
+
+	publicacaoSalvaNoBanco, erro := repositorio.BuscarPorId(publicacaoId)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
 		return
 	}
-	defer db.Close()
 
-	repositorio := repositorios.NovoRepositorioDePublicacoes(db)
+	if publicacaoSalvaNoBanco.ID == 0 {
+		respostas.ERRO(w, r, http.StatusNotFound, respostas.NovoErro(respostas.CodigoPublicacaoNaoEncontrada, "publicação não encontrada"))
+		return
+	}
+
+	if !controller.publicacaoNaoOculta(w, r, usuarioId, publicacaoSalvaNoBanco) {
+		return
+	}
+
+	if !controller.publicacaoSemBloqueio(w, r, usuarioId, publicacaoSalvaNoBanco.AuthorId) {
+		return
+	}
+
+	if !controller.conteudoVisivel(w, r, usuarioId, publicacaoSalvaNoBanco.AuthorId) {
+		return
+	}
 
-	if erro = repositorio.Curtir(publicacaoId); erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+	if erro = repositorio.Curtir(usuarioId, publicacaoId); erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
 		return
 	}
 
@@ -286,26 +347,25 @@ func CurtirPublicacao(w http.ResponseWriter, r *http.Request) {
 	respostas.JSON(w, http.StatusNoContent, nil, headers)
 }
 
-// DescurtirPublicacao decrementa curtida em 1
-func DescurtirPublicacao(w http.ResponseWriter, r *http.Request) {
-	parametros := mux.Vars(r)
-	publicacaoId, erro := strconv.ParseUint(parametros["publicacaoId"], 10, 64)
+// DescurtirPublicacao remove a curtida do usuário logado na publicação
+func (controller Controller) DescurtirPublicacao(w http.ResponseWriter, r *http.Request) {
+	usuarioId, erro := autenticacao.ExtrairUsuarioId(r)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusBadRequest, erro)
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
 		return
 	}
 
-	db, erro := banco.Conectar()
+	parametros := mux.Vars(r)
+	publicacaoId, erro := strconv.ParseUint(parametros["publicacaoId"], 10, 64)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "publicacaoId inválido"))
 		return
 	}
-	defer db.Close()
 
-	repositorio := repositorios.NovoRepositorioDePublicacoes(db)
+	repositorio := controller.publicacoes
 
-	if erro = repositorio.Descurtir(publicacaoId); erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+	if erro = repositorio.Descurtir(usuarioId, publicacaoId); erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
 		return
 	}
 
@@ -316,4 +376,59 @@ func DescurtirPublicacao(w http.ResponseWriter, r *http.Request) {
 		"location": fmt.Sprintf("%s:%d/publicacoes/%d", host, portaApi, publicacaoId),
 	}
 	respostas.JSON(w, http.StatusNoContent, nil, headers)
-}
\ No newline at end of file
+}
+
+// BuscarCurtidas retorna os usuários que curtiram uma publicação
+func (controller Controller) BuscarCurtidas(w http.ResponseWriter, r *http.Request) {
+	usuarioLogadoId, erro := autenticacao.ExtrairUsuarioId(r)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
+		return
+	}
+
+	parametros := mux.Vars(r)
+	publicacaoId, erro := strconv.ParseUint(parametros["publicacaoId"], 10, 64)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "publicacaoId inválido"))
+		return
+	}
+
+	repositorio := controller.publicacoes
+
+	publicacao, erro := repositorio.BuscarPorId(publicacaoId)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	if publicacao.ID == 0 {
+		respostas.ERRO(w, r, http.StatusNotFound, respostas.NovoErro(respostas.CodigoPublicacaoNaoEncontrada, "publicação não encontrada"))
+		return
+	}
+
+	if !controller.publicacaoNaoOculta(w, r, usuarioLogadoId, publicacao) {
+		return
+	}
+
+	if !controller.publicacaoSemBloqueio(w, r, usuarioLogadoId, publicacao.AuthorId) {
+		return
+	}
+
+	if !controller.conteudoVisivel(w, r, usuarioLogadoId, publicacao.AuthorId) {
+		return
+	}
+
+	paginacao, erro := modelos.NovaPaginacao(r.URL.Query().Get("limite"), r.URL.Query().Get("cursor"))
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
+		return
+	}
+
+	usuarios, proximoCursor, erro := repositorio.BuscarCurtidas(publicacaoId, paginacao)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	respostas.JSON(w, http.StatusOK, respostas.Pagina{Dados: usuarios, ProximoCursor: proximoCursor.Codificar()}, nil)
+}
@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"api/src/logs"
+	"api/src/respostas"
+	"api/src/saude"
+	"api/src/versao"
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// tempoMaximoPing limita a verificação do banco para que a sonda de prontidão não fique presa num pool esgotado
+const tempoMaximoPing = 2 * time.Second
+
+// respostaSaude é o corpo das sondas de vida e prontidão
+type respostaSaude struct {
+	Status       string            `json:"status"`
+	Verificacoes map[string]string `json:"verificacoes,omitempty"`
+}
+
+// respostaVersao identifica o binário em execução
+type respostaVersao struct {
+	Commit      string `json:"commit"`
+	CompiladoEm string `json:"compiladoEm"`
+}
+
+// VerificarVida indica que o processo está no ar e atendendo requisições
+func (controller Controller) VerificarVida(w http.ResponseWriter, r *http.Request) {
+	respostas.JSON(w, http.StatusOK, respostaSaude{Status: "ok"}, nil)
+}
+
+// VerificarProntidao indica se a API pode receber tráfego: não está encerrando, o MySQL responde
+// e não há migrações pendentes
+func (controller Controller) VerificarProntidao(w http.ResponseWriter, r *http.Request) {
+	verificacoes := map[string]string{
+		"servidor":  "ok",
+		"banco":     "ok",
+		"migracoes": "ok",
+	}
+	pronto := true
+
+	if !saude.Pronto() {
+		verificacoes["servidor"] = "encerrando"
+		pronto = false
+	}
+
+	ctx, cancelar := context.WithTimeout(r.Context(), tempoMaximoPing)
+	defer cancelar()
+
+	if erro := controller.saude.Pingar(ctx); erro != nil {
+		slog.WarnContext(r.Context(), "banco indisponível", append(logs.Atributos(r.Context()), slog.Any("erro", erro))...)
+		verificacoes["banco"] = "indisponível"
+		verificacoes["migracoes"] = "não verificadas"
+		pronto = false
+	} else if pendentes, erro := controller.saude.MigracoesPendentes(ctx); erro != nil {
+		slog.WarnContext(r.Context(), "falha ao verificar migrações", append(logs.Atributos(r.Context()), slog.Any("erro", erro))...)
+		verificacoes["migracoes"] = "não verificadas"
+		pronto = false
+	} else if pendentes > 0 {
+		verificacoes["migracoes"] = "pendentes"
+		pronto = false
+	}
+
+	if !pronto {
+		respostas.JSON(w, http.StatusServiceUnavailable, respostaSaude{Status: "indisponível", Verificacoes: verificacoes}, nil)
+		return
+	}
+
+	respostas.JSON(w, http.StatusOK, respostaSaude{Status: "ok", Verificacoes: verificacoes}, nil)
+}
+
+// BuscarVersao retorna o commit e a data de compilação do binário em execução
+func (controller Controller) BuscarVersao(w http.ResponseWriter, r *http.Request) {
+	respostas.JSON(w, http.StatusOK, respostaVersao{Commit: versao.Commit, CompiladoEm: versao.CompiladoEm}, nil)
+}
@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"api/src/autenticacao"
+	"api/src/config"
+	"api/src/email"
+	"api/src/logs"
+	"api/src/modelos"
+	"api/src/respostas"
+	"api/src/seguranca"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// EsqueciSenha envia ao usuário um email com o token de redefinição de senha. A resposta é sempre 200,
+// exista ou não uma conta com o email informado, para não revelar quais emails estão cadastrados.
+func (controller Controller) EsqueciSenha(w http.ResponseWriter, r *http.Request) {
+	corpoDaRequisicao, erro := io.ReadAll(r.Body)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnprocessableEntity, erro)
+		return
+	}
+
+	var requisicao requisicaoEsqueciSenha
+	if erro = json.Unmarshal(corpoDaRequisicao, &requisicao); erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
+		return
+	}
+
+	requisicao.Email = strings.TrimSpace(requisicao.Email)
+	if requisicao.Email == "" {
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoDadosInvalidos, "email obrigatório"))
+		return
+	}
+
+	usuario, erro := controller.usuarios.BuscarPorEmail(requisicao.Email)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	// A gravação do token e o envio são feitos fora da requisição: o tempo de resposta não pode indicar se a conta existe
+	if usuario.ID != 0 {
+		ctx := context.WithoutCancel(r.Context())
+		controller.emSegundoPlano(func() { controller.enviarRedefinicao(ctx, usuario.ID, usuario.Email) })
+	}
+
+	respostas.JSON(w, http.StatusOK, nil, nil)
+}
+
+// RedefinirSenha troca a senha do usuário a partir do token enviado por email.
+// O token é de uso único e todas as sessões do usuário são encerradas.
+func (controller Controller) RedefinirSenha(w http.ResponseWriter, r *http.Request) {
+	corpoDaRequisicao, erro := io.ReadAll(r.Body)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnprocessableEntity, erro)
+		return
+	}
+
+	var requisicao requisicaoRedefinirSenha
+	if erro = json.Unmarshal(corpoDaRequisicao, &requisicao); erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
+		return
+	}
+
+	if requisicao.Token == "" {
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoDadosInvalidos, "token obrigatório"))
+		return
+	}
+
+	repositorio := controller.tokens
+
+	token, erro := repositorio.BuscarTokenDeRedefinicao(autenticacao.HashTokenDeRedefinicao(requisicao.Token))
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	if !token.Valido() {
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoTokenInvalido, "token de redefinição inválido ou expirado"))
+		return
+	}
+
+	usuario, erro := controller.usuarios.BuscarPorId(token.UsuarioId)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	// A política é verificada antes de usar o token, para que uma senha recusada não o desperdice
+	if erro = (modelos.Senha{Nova: requisicao.Nova}).Validar(usuario.Nick, usuario.Email); erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
+		return
+	}
+
+	senhaComHash, erro := seguranca.Hash(requisicao.Nova)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	// A troca da senha, o uso do token e o encerramento das sessões são gravados juntos: se algo falhar,
+	// o token continua valendo. Quem pediu a redefinição pode não ser quem está com as sessões abertas.
+	redefinida, erro := repositorio.RedefinirSenha(token, string(senhaComHash), time.Now().Add(config.DuracaoToken))
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	// Outra requisição usou o mesmo token entre a busca e a redefinição
+	if !redefinida {
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoTokenInvalido, "token de redefinição inválido ou expirado"))
+		return
+	}
+
+	respostas.JSON(w, http.StatusNoContent, nil, nil)
+}
+
+// enviarRedefinicao gera e persiste o token de redefinição e o envia por email. Falhas são registradas
+// no log e não alteram a resposta, que não pode revelar se o email está cadastrado.
+func (controller Controller) enviarRedefinicao(ctx context.Context, usuarioId uint64, emailDoUsuario string) {
+	valor, tokenHash, erro := autenticacao.CriarTokenDeRedefinicao()
+	if erro == nil {
+		erro = controller.tokens.CriarTokenDeRedefinicao(modelos.TokenDeRedefinicao{
+			UsuarioId: usuarioId,
+			TokenHash: tokenHash,
+			ExpiraEm:  time.Now().Add(config.DuracaoTokenRedefinicao),
+		})
+	}
+
+	if erro == nil {
+		erro = controller.mailer.Enviar(email.Mensagem{
+			Para:    emailDoUsuario,
+			Assunto: "Redefinição de senha no DevBook",
+			Corpo:   corpoDaRedefinicao(valor),
+		})
+	}
+
+	if erro != nil {
+		slog.ErrorContext(ctx, "falha ao enviar email de redefinição de senha", append(logs.Atributos(ctx),
+			slog.Uint64("destinatarioId", usuarioId),
+			slog.Any("erro", erro),
+		)...)
+	}
+}
+
+// corpoDaRedefinicao monta o texto do email de redefinição de senha
+func corpoDaRedefinicao(token string) string {
+	return fmt.Sprintf("Olá!\n\n%s\n\nO token expira em %s e só pode ser usado uma vez. "+
+		"Se você não pediu a redefinição, ignore este email: sua senha continua a mesma.\n",
+		instrucaoComToken("escolher uma nova senha", "/redefinir-senha", token), config.DuracaoTokenRedefinicao)
+}
+
+type requisicaoEsqueciSenha struct {
+	Email string `json:"email"`
+}
+
+type requisicaoRedefinirSenha struct {
+	Token string `json:"token"`
+	Nova  string `json:"nova"`
+}
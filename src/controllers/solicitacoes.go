@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"api/src/autenticacao"
+	"api/src/modelos"
+	"api/src/respostas"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// BuscarSolicitacoes retorna os pedidos para seguir pendentes recebidos pelo usuário logado
+func (controller Controller) BuscarSolicitacoes(w http.ResponseWriter, r *http.Request) {
+	usuarioId, erro := autenticacao.ExtrairUsuarioId(r)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
+		return
+	}
+
+	paginacao, erro := modelos.NovaPaginacao(r.URL.Query().Get("limite"), r.URL.Query().Get("cursor"))
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
+		return
+	}
+
+	solicitantes, proximoCursor, erro := controller.usuarios.BuscarSolicitacoes(usuarioId, paginacao)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	respostas.JSON(w, http.StatusOK, respostas.Pagina{Dados: solicitantes, ProximoCursor: proximoCursor.Codificar()}, nil)
+}
+
+// AceitarSolicitacao aceita o pedido para seguir feito pelo usuário da rota, que passa a seguir o usuário logado
+func (controller Controller) AceitarSolicitacao(w http.ResponseWriter, r *http.Request) {
+	usuarioId, solicitanteId, ok := lerSolicitacao(w, r)
+	if !ok {
+		return
+	}
+
+	aceita, erro := controller.usuarios.AceitarSolicitacao(usuarioId, solicitanteId)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	if !aceita {
+		respostas.ERRO(w, r, http.StatusNotFound, respostas.NovoErro(respostas.CodigoSolicitacaoNaoEncontrada, "solicitação não encontrada"))
+		return
+	}
+
+	respostas.JSON(w, http.StatusNoContent, nil, nil)
+}
+
+// RecusarSolicitacao descarta o pedido para seguir feito pelo usuário da rota
+func (controller Controller) RecusarSolicitacao(w http.ResponseWriter, r *http.Request) {
+	usuarioId, solicitanteId, ok := lerSolicitacao(w, r)
+	if !ok {
+		return
+	}
+
+	removida, erro := controller.usuarios.RemoverSolicitacao(usuarioId, solicitanteId)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	if !removida {
+		respostas.ERRO(w, r, http.StatusNotFound, respostas.NovoErro(respostas.CodigoSolicitacaoNaoEncontrada, "solicitação não encontrada"))
+		return
+	}
+
+	respostas.JSON(w, http.StatusNoContent, nil, nil)
+}
+
+// lerSolicitacao obtém o usuário logado e o solicitante informado na rota.
+// Em caso de falha a resposta de erro já é escrita e ok é falso.
+func lerSolicitacao(w http.ResponseWriter, r *http.Request) (uint64, uint64, bool) {
+	usuarioId, erro := autenticacao.ExtrairUsuarioId(r)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
+		return 0, 0, false
+	}
+
+	parametros := mux.Vars(r)
+	solicitanteId, erro := strconv.ParseUint(parametros["usuarioId"], 10, 64)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "usuarioId inválido"))
+		return 0, 0, false
+	}
+
+	return usuarioId, solicitanteId, true
+}
+
+// conteudoVisivel responde 403 quando o usuário consultado tem conta privada e o usuário logado
+// não é ele nem um de seus seguidores. Retorna falso se a resposta de erro já foi escrita.
+func (controller Controller) conteudoVisivel(w http.ResponseWriter, r *http.Request, usuarioLogadoId, usuarioId uint64) bool {
+	if usuarioLogadoId == usuarioId {
+		return true
+	}
+
+	usuario, erro := controller.usuarios.BuscarPorId(usuarioId)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return false
+	}
+
+	if !usuario.ContaPrivada() {
+		return true
+	}
+
+	segue, erro := controller.usuarios.Segue(usuarioId, usuarioLogadoId)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return false
+	}
+
+	if !segue {
+		respostas.ERRO(w, r, http.StatusForbidden, respostas.NovoErro(respostas.CodigoContaPrivada, "esta conta é privada"))
+		return false
+	}
+
+	return true
+}
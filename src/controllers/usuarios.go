@@ -2,12 +2,13 @@ package controllers
 
 import (
 	"api/src/autenticacao"
-	"api/src/banco"
 	"api/src/config"
+	"api/src/metricas"
 	"api/src/modelos"
 	"api/src/repositorios"
 	"api/src/respostas"
 	"api/src/seguranca"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -15,44 +16,46 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 )
 
 // CriarUsuario recurso para criar usuário
-func CriarUsuario(w http.ResponseWriter, r *http.Request) {
+func (controller Controller) CriarUsuario(w http.ResponseWriter, r *http.Request) {
 
 	corpoRequest, erro := io.ReadAll(r.Body)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusUnprocessableEntity, erro)
+		respostas.ERRO(w, r, http.StatusUnprocessableEntity, erro)
 		return
 	}
 
 	var usuario modelos.Usuario
 	if erro = json.Unmarshal(corpoRequest, &usuario); erro != nil {
-		respostas.ERRO(w, http.StatusBadRequest, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
 		return
 	}
 
 	if erro = usuario.Preparar("cadastro"); erro != nil {
-		respostas.ERRO(w, http.StatusBadRequest, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
 		return
 	}
 
-	db, erro := banco.Conectar()
-	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
-		return
-	}
-	defer db.Close()
-
-	repositorio := repositorios.NovoRepositorioDeUsuarios(db)
+	repositorio := controller.usuarios
 	usuarioId, erro := repositorio.Criar(usuario)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		var erroDeDuplicidade repositorios.ErroDeDuplicidade
+		if errors.As(erro, &erroDeDuplicidade) {
+			respostas.ERRO(w, r, http.StatusConflict, erroDeConflito(erroDeDuplicidade))
+			return
+		}
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
 		return
 	}
 
+	ctx := context.WithoutCancel(r.Context())
+	controller.emSegundoPlano(func() { controller.enviarVerificacao(ctx, usuarioId, usuario.Email) })
+
 	host := config.Host
 	portaApi := config.Porta
 
@@ -62,55 +65,100 @@ func CriarUsuario(w http.ResponseWriter, r *http.Request) {
 	respostas.JSON(w, http.StatusCreated, nil, headers)
 }
 
+// VerificarDisponibilidade informa se nick e/ou email ainda podem ser utilizados em um cadastro
+func (controller Controller) VerificarDisponibilidade(w http.ResponseWriter, r *http.Request) {
+	nick := strings.TrimSpace(r.URL.Query().Get("nick"))
+	email := strings.TrimSpace(r.URL.Query().Get("email"))
+
+	if nick == "" && email == "" {
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "informe nick ou email"))
+		return
+	}
+
+	repositorio := controller.usuarios
+
+	var disponibilidade respostaDisponibilidade
+
+	if nick != "" {
+		emUso, erro := repositorio.NickEmUso(nick)
+		if erro != nil {
+			respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+			return
+		}
+		disponivel := !emUso
+		disponibilidade.Nick = &disponivel
+	}
+
+	if email != "" {
+		emUso, erro := repositorio.EmailEmUso(email)
+		if erro != nil {
+			respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+			return
+		}
+		disponivel := !emUso
+		disponibilidade.Email = &disponivel
+	}
+
+	respostas.JSON(w, http.StatusOK, disponibilidade, nil)
+}
+
 // BuscarUsuarios recurso para buscar todos os usuários
-func BuscarUsuarios(w http.ResponseWriter, r *http.Request) {
+func (controller Controller) BuscarUsuarios(w http.ResponseWriter, r *http.Request) {
+	usuarioLogadoId, erro := autenticacao.ExtrairUsuarioId(r)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
+		return
+	}
+
 	nomeOuNick := strings.ToLower(r.URL.Query().Get("usuario"))
 
-	db, erro := banco.Conectar()
+	repositorio := controller.usuarios
+
+	paginacao, erro := modelos.NovaPaginacao(r.URL.Query().Get("limite"), r.URL.Query().Get("cursor"))
 	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
 		return
 	}
-	defer db.Close()
-
-	repositorio := repositorios.NovoRepositorioDeUsuarios(db)
 
-	usuarios, erro := repositorio.Buscar(nomeOuNick)
+	usuarios, proximoCursor, erro := repositorio.Buscar(nomeOuNick, usuarioLogadoId, paginacao)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
 		return
 	}
 
-	respostas.JSON(w, http.StatusOK, usuarios, nil)
+	respostas.JSON(w, http.StatusOK, respostas.Pagina{Dados: usuarios, ProximoCursor: proximoCursor.Codificar()}, nil)
 }
 
 // BuscarUsuario recurso para buscar dados de um usuário
-func BuscarUsuario(w http.ResponseWriter, r *http.Request) {
+func (controller Controller) BuscarUsuario(w http.ResponseWriter, r *http.Request) {
+	usuarioLogadoId, erro := autenticacao.ExtrairUsuarioId(r)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
+		return
+	}
+
 	parametros := mux.Vars(r)
 
 	usuarioId, erro := strconv.ParseUint(parametros["usuarioId"], 10, 64)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusBadRequest, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "usuarioId inválido"))
 		return
 	}
 
-	db, erro := banco.Conectar()
-	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+	if !controller.perfilVisivel(w, r, usuarioLogadoId, usuarioId) {
 		return
 	}
-	defer db.Close()
 
-	repositorio := repositorios.NovoRepositorioDeUsuarios(db)
+	repositorio := controller.usuarios
 
 	usuario, erro := repositorio.BuscarPorId(usuarioId)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
 		return
 	}
 
 	if usuario.ID == 0 {
-		respostas.ERRO(w, http.StatusNotFound, errors.New("usuário inexistente"))
+		respostas.ERRO(w, r, http.StatusNotFound, respostas.NovoErro(respostas.CodigoUsuarioNaoEncontrado, "usuário inexistente"))
 		return
 	}
 
@@ -118,114 +166,111 @@ func BuscarUsuario(w http.ResponseWriter, r *http.Request) {
 }
 
 // AtualizarUsuario recurso para atualizar dados de um usuário
-func AtualizarUsuario(w http.ResponseWriter, r *http.Request) {
+func (controller Controller) AtualizarUsuario(w http.ResponseWriter, r *http.Request) {
 	parametros := mux.Vars(r)
 
 	usuarioId, erro := strconv.ParseUint(parametros["usuarioId"], 10, 64)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusBadRequest, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "usuarioId inválido"))
 		return
 	}
 
 	usuarioIdNoToken, erro := autenticacao.ExtrairUsuarioId(r)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusUnauthorized, erro)
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
 		return
 	}
 
 	if usuarioId != usuarioIdNoToken {
-		respostas.ERRO(w, http.StatusForbidden, errors.New("token inválido"))
+		respostas.ERRO(w, r, http.StatusForbidden, respostas.NovoErro(respostas.CodigoAcessoNegado, "token inválido"))
 		return
 	}
 
 	corpoRequest, erro := io.ReadAll(r.Body)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusUnprocessableEntity, erro)
+		respostas.ERRO(w, r, http.StatusUnprocessableEntity, erro)
 		return
 	}
 
 	var usuario modelos.Usuario
 	if erro = json.Unmarshal(corpoRequest, &usuario); erro != nil {
-		respostas.ERRO(w, http.StatusBadRequest, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
 		return
 	}
 
 	if erro = usuario.Preparar("edicao"); erro != nil {
-		respostas.ERRO(w, http.StatusBadRequest, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
 		return
 	}
 
-	db, erro := banco.Conectar()
-	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
-		return
-	}
-	defer db.Close()
-
-	repositorio := repositorios.NovoRepositorioDeUsuarios(db)
+	repositorio := controller.usuarios
 
 	usuarioNaBase, erro := repositorio.BuscarPorId(usuarioId)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
 		return
 	}
 
 	if usuarioNaBase.ID == 0 {
-		respostas.ERRO(w, http.StatusBadRequest, errors.New("usuário inexistente"))
+		respostas.ERRO(w, r, http.StatusNotFound, respostas.NovoErro(respostas.CodigoUsuarioNaoEncontrado, "usuário inexistente"))
 		return
 	}
 
 	if erro = repositorio.Atualizar(usuarioId, usuario); erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		var erroDeDuplicidade repositorios.ErroDeDuplicidade
+		if errors.As(erro, &erroDeDuplicidade) {
+			respostas.ERRO(w, r, http.StatusConflict, erroDeConflito(erroDeDuplicidade))
+			return
+		}
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
 		return
 	}
 
+	// O novo email precisa ser confirmado
+	if usuario.Email != usuarioNaBase.Email {
+		ctx := context.WithoutCancel(r.Context())
+		controller.emSegundoPlano(func() { controller.enviarVerificacao(ctx, usuarioId, usuario.Email) })
+	}
+
 	respostas.JSON(w, http.StatusNoContent, nil, nil)
 }
 
 // RemoverUsuario recurso para remover os dados de um usuário
-func RemoverUsuario(w http.ResponseWriter, r *http.Request) {
+func (controller Controller) RemoverUsuario(w http.ResponseWriter, r *http.Request) {
 	parametros := mux.Vars(r)
 
 	usuarioId, erro := strconv.ParseUint(parametros["usuarioId"], 10, 64)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusBadRequest, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "usuarioId inválido"))
 		return
 	}
 
 	usuarioIdNoToken, erro := autenticacao.ExtrairUsuarioId(r)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusUnauthorized, erro)
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
 		return
 	}
 
 	if usuarioId != usuarioIdNoToken {
-		respostas.ERRO(w, http.StatusForbidden, errors.New("token inválido"))
-		return
-	}
-
-	db, erro := banco.Conectar()
-	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusForbidden, respostas.NovoErro(respostas.CodigoAcessoNegado, "token inválido"))
 		return
 	}
-	defer db.Close()
 
-	repositorio := repositorios.NovoRepositorioDeUsuarios(db)
+	repositorio := controller.usuarios
 
 	usuarioNaBase, erro := repositorio.BuscarPorId(usuarioId)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
 		return
 	}
 
 	if usuarioNaBase.ID == 0 {
-		respostas.ERRO(w, http.StatusBadRequest, errors.New("usuário inexistente"))
+		respostas.ERRO(w, r, http.StatusNotFound, respostas.NovoErro(respostas.CodigoUsuarioNaoEncontrado, "usuário inexistente"))
 		return
 	}
 
 	if erro = repositorio.RemoverUsuario(usuarioId); erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
 		return
 	}
 
@@ -233,10 +278,10 @@ func RemoverUsuario(w http.ResponseWriter, r *http.Request) {
 }
 
 // SeguirUsuario permite que um usuário siga outro
-func SeguirUsuario(w http.ResponseWriter, r *http.Request) {
+func (controller Controller) SeguirUsuario(w http.ResponseWriter, r *http.Request) {
 	seguidorId, erro := autenticacao.ExtrairUsuarioId(r)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusUnauthorized, erro)
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
 		return
 	}
 
@@ -244,63 +289,97 @@ func SeguirUsuario(w http.ResponseWriter, r *http.Request) {
 
 	usuarioId, erro := strconv.ParseUint(parametros["usuarioId"], 10, 64)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusBadRequest, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "usuarioId inválido"))
 		return
 	}
 
 	if usuarioId == seguidorId {
-		respostas.ERRO(w, http.StatusForbidden, errors.New("não é possível seguir a si mesmo"))
+		respostas.ERRO(w, r, http.StatusForbidden, respostas.NovoErro(respostas.CodigoAcessoNegado, "não é possível seguir a si mesmo"))
 		return
 	}
 
-	db, erro := banco.Conectar()
+	repositorio := controller.usuarios
+
+	bloqueado, erro := repositorio.Bloqueado(seguidorId, usuarioId)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	if bloqueado {
+		respostas.ERRO(w, r, http.StatusForbidden, respostas.NovoErro(respostas.CodigoUsuarioBloqueado, "não é possível seguir este usuário"))
+		return
+	}
+
+	usuario, erro := repositorio.BuscarPorId(usuarioId)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	if usuario.ID == 0 {
+		respostas.ERRO(w, r, http.StatusNotFound, respostas.NovoErro(respostas.CodigoUsuarioNaoEncontrado, "usuário inexistente"))
 		return
 	}
-	defer db.Close()
 
-	repositorio := repositorios.NovoRepositorioDeUsuarios(db)
-	if erro := repositorio.Seguir(seguidorId, usuarioId); erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+	// Conta privada: o pedido fica pendente até ser aceito pelo dono da conta
+	if usuario.ContaPrivada() {
+		segue, erro := repositorio.Segue(usuarioId, seguidorId)
+		if erro != nil {
+			respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+			return
+		}
+
+		if !segue {
+			if erro = repositorio.SolicitarSeguir(usuarioId, seguidorId); erro != nil {
+				respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+				return
+			}
+
+			respostas.JSON(w, http.StatusAccepted, nil, nil)
+			return
+		}
+	}
+
+	if erro := repositorio.Seguir(usuarioId, seguidorId); erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
 		return
 	}
+	metricas.UsuarioSeguido()
 
 	respostas.JSON(w, http.StatusNoContent, nil, nil)
 }
 
-// PararDeSeguirUsuario permite um usuário deixar de seguir outro
-func PararDeSeguirUsuario(w http.ResponseWriter, r *http.Request) {
-	usuarioId, erro := autenticacao.ExtrairUsuarioId(r)
+// PararDeSeguirUsuario permite um usuário deixar de seguir outro. Também cancela o pedido para seguir pendente, se houver.
+func (controller Controller) PararDeSeguirUsuario(w http.ResponseWriter, r *http.Request) {
+	seguidorId, erro := autenticacao.ExtrairUsuarioId(r)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusUnauthorized, erro)
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
 		return
 	}
 
 	parametros := mux.Vars(r)
 
-	seguidorId, erro := strconv.ParseUint(parametros["usuarioId"], 10, 64)
+	usuarioId, erro := strconv.ParseUint(parametros["usuarioId"], 10, 64)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusBadRequest, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "usuarioId inválido"))
 		return
 	}
 
 	if seguidorId == usuarioId {
-		respostas.ERRO(w, http.StatusForbidden, errors.New("não é possível parar de seguir a si mesmo"))
+		respostas.ERRO(w, r, http.StatusForbidden, respostas.NovoErro(respostas.CodigoAcessoNegado, "não é possível parar de seguir a si mesmo"))
 		return
 	}
 
-	db, erro := banco.Conectar()
-	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+	repositorio := controller.usuarios
+
+	if erro := repositorio.PararDeSeguir(usuarioId, seguidorId); erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
 		return
 	}
-	defer db.Close()
-
-	repositorio := repositorios.NovoRepositorioDeUsuarios(db)
 
-	if erro := repositorio.PararDeSeguir(usuarioId, seguidorId); erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+	if _, erro := repositorio.RemoverSolicitacao(usuarioId, seguidorId); erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
 		return
 	}
 
@@ -308,65 +387,91 @@ func PararDeSeguirUsuario(w http.ResponseWriter, r *http.Request) {
 }
 
 // BuscarSeguidores traz todos os seguidores de um usuário
-func BuscarSeguidores(w http.ResponseWriter, r *http.Request) {
+func (controller Controller) BuscarSeguidores(w http.ResponseWriter, r *http.Request) {
+	usuarioLogadoId, erro := autenticacao.ExtrairUsuarioId(r)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
+		return
+	}
+
 	parametros := mux.Vars(r)
 
 	usuarioId, erro := strconv.ParseUint(parametros["usuarioId"], 10, 64)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusBadRequest, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "usuarioId inválido"))
 		return
 	}
 
-	db, erro := banco.Conectar()
+	if !controller.perfilVisivel(w, r, usuarioLogadoId, usuarioId) {
+		return
+	}
+
+	if !controller.conteudoVisivel(w, r, usuarioLogadoId, usuarioId) {
+		return
+	}
+
+	paginacao, erro := modelos.NovaPaginacao(r.URL.Query().Get("limite"), r.URL.Query().Get("cursor"))
 	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
 		return
 	}
-	defer db.Close()
 
-	repositorio := repositorios.NovoRepositorioDeUsuarios(db)
-	seguidores, erro := repositorio.BuscarSeguidores(usuarioId)
+	repositorio := controller.usuarios
+	seguidores, proximoCursor, erro := repositorio.BuscarSeguidores(usuarioId, paginacao)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
 		return
 	}
 
-	respostas.JSON(w, http.StatusOK, seguidores, nil)
+	respostas.JSON(w, http.StatusOK, respostas.Pagina{Dados: seguidores, ProximoCursor: proximoCursor.Codificar()}, nil)
 }
 
 // BuscarSeguidos retorna a lista dos usuarios que seguem o usuário do request
-func BuscarSeguidos(w http.ResponseWriter, r *http.Request) {
+func (controller Controller) BuscarSeguidos(w http.ResponseWriter, r *http.Request) {
+	usuarioLogadoId, erro := autenticacao.ExtrairUsuarioId(r)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
+		return
+	}
+
 	parametros := mux.Vars(r)
 
 	usuarioId, erro := strconv.ParseUint(parametros["usuarioId"], 10, 64)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusBadRequest, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "usuarioId inválido"))
 		return
 	}
 
-	db, erro := banco.Conectar()
+	if !controller.perfilVisivel(w, r, usuarioLogadoId, usuarioId) {
+		return
+	}
+
+	if !controller.conteudoVisivel(w, r, usuarioLogadoId, usuarioId) {
+		return
+	}
+
+	paginacao, erro := modelos.NovaPaginacao(r.URL.Query().Get("limite"), r.URL.Query().Get("cursor"))
 	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
 		return
 	}
-	defer db.Close()
 
-	repositorio := repositorios.NovoRepositorioDeUsuarios(db)
-	seguidores, erro := repositorio.BuscarSeguindo(usuarioId)
+	repositorio := controller.usuarios
+	seguidores, proximoCursor, erro := repositorio.BuscarSeguindo(usuarioId, paginacao)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
 		return
 	}
 
-	respostas.JSON(w, http.StatusOK, seguidores, nil)
+	respostas.JSON(w, http.StatusOK, respostas.Pagina{Dados: seguidores, ProximoCursor: proximoCursor.Codificar()}, nil)
 }
 
 // AtualizarSenha atualiza a senha do usuário
-func AtualizarSenha (w http.ResponseWriter, r *http.Request) {
+func (controller Controller) AtualizarSenha(w http.ResponseWriter, r *http.Request) {
 
 	usuarioIdNoToken, erro := autenticacao.ExtrairUsuarioId(r)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusUnauthorized, erro)
+		respostas.ERRO(w, r, http.StatusUnauthorized, erro)
 		return
 	}
 
@@ -374,56 +479,98 @@ func AtualizarSenha (w http.ResponseWriter, r *http.Request) {
 
 	usuarioId, erro := strconv.ParseUint(parametros["usuarioId"], 10, 64)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusBadRequest, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoParametroInvalido, "usuarioId inválido"))
 		return
 	}	
 
 	if usuarioId != usuarioIdNoToken {
-		respostas.ERRO(w, http.StatusForbidden, errors.New("não foi possível atualizar a senha"))
+		respostas.ERRO(w, r, http.StatusForbidden, respostas.NovoErro(respostas.CodigoAcessoNegado, "não foi possível atualizar a senha"))
 		return
 	}
 
 	corpoRequest, erro := io.ReadAll(r.Body)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusUnprocessableEntity, erro)
+		respostas.ERRO(w, r, http.StatusUnprocessableEntity, erro)
 		return
 	}
 
 	var senha modelos.Senha
 	if erro = json.Unmarshal(corpoRequest, &senha); erro != nil {
-		respostas.ERRO(w, http.StatusBadRequest, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
 		return
 	}
 
-	db, erro := banco.Conectar()
+	repositorio := controller.usuarios
+
+	usuario, erro := repositorio.BuscarPorId(usuarioId)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	if usuario.ID == 0 {
+		respostas.ERRO(w, r, http.StatusNotFound, respostas.NovoErro(respostas.CodigoUsuarioNaoEncontrado, "usuário inexistente"))
 		return
 	}
-	defer db.Close()
 
-	repositorio := repositorios.NovoRepositorioDeUsuarios(db)
 	senhaSalvaNoBanco, erro := repositorio.BuscarSenha(usuarioId)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
 		return
 	}
 
 	if erro = seguranca.VerificarSenha(senhaSalvaNoBanco, senha.Atual); erro != nil {
-		respostas.ERRO(w, http.StatusUnauthorized, errors.New("senha inválida"))
+		respostas.ERRO(w, r, http.StatusUnauthorized, respostas.NovoErro(respostas.CodigoCredenciaisInvalidas, "senha inválida"))
+		return
+	}
+
+	// A política só é avaliada depois da senha atual: sem ela, a resposta não diz nada sobre a nova
+	if erro = senha.Validar(usuario.Nick, usuario.Email); erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
 		return
 	}
 
 	senhaComHash, erro := seguranca.Hash(senha.Nova)
 	if erro != nil {
-		respostas.ERRO(w, http.StatusBadRequest, erro)
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
 		return
 	}
 
 	erro = repositorio.AtualizarSenha(usuarioId, string(senhaComHash)); if erro != nil {
-		respostas.ERRO(w, http.StatusInternalServerError, erro)
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	// Troca de senha encerra todas as sessões: tokens emitidos antes da troca deixam de ser aceitos.
+	// Os tokens de acesso de refresh tokens já renovados foram revogados na renovação.
+	if erro = controller.tokens.RevogarSessoesDoUsuario(usuarioId, time.Now().Add(config.DuracaoToken)); erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
 		return
 	}
 
 	respostas.JSON(w, http.StatusOK, nil, nil)
-}
\ No newline at end of file
+}
+
+type respostaDisponibilidade struct {
+	Nick  *bool `json:"nickDisponivel,omitempty"`
+	Email *bool `json:"emailDisponivel,omitempty"`
+}
+
+// erroDeConflito converte a violação de campo único em erro da API, indicando o campo em conflito
+func erroDeConflito(erroDeDuplicidade repositorios.ErroDeDuplicidade) *respostas.Erro {
+	codigo := respostas.CodigoConflito
+	switch erroDeDuplicidade.Campo {
+	case "nick":
+		codigo = respostas.CodigoNickDuplicado
+	case "email":
+		codigo = respostas.CodigoEmailDuplicado
+	}
+
+	return &respostas.Erro{
+		Codigo:   codigo,
+		Mensagem: erroDeDuplicidade.Error(),
+		Campos: []modelos.CampoInvalido{
+			{Campo: erroDeDuplicidade.Campo, Mensagem: erroDeDuplicidade.Error()},
+		},
+	}
+}
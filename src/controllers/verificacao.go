@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"api/src/autenticacao"
+	"api/src/config"
+	"api/src/email"
+	"api/src/logs"
+	"api/src/respostas"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// VerificarEmail confirma o email do usuário a partir do token enviado por email
+func (controller Controller) VerificarEmail(w http.ResponseWriter, r *http.Request) {
+	corpoDaRequisicao, erro := io.ReadAll(r.Body)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnprocessableEntity, erro)
+		return
+	}
+
+	var requisicao requisicaoVerificacao
+	if erro = json.Unmarshal(corpoDaRequisicao, &requisicao); erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
+		return
+	}
+
+	if requisicao.Token == "" {
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoDadosInvalidos, "token obrigatório"))
+		return
+	}
+
+	usuarioId, emailDoToken, erro := autenticacao.ValidarTokenDeVerificacao(requisicao.Token)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoTokenInvalido, "token de verificação inválido ou expirado"))
+		return
+	}
+
+	verificado, erro := controller.usuarios.VerificarEmail(usuarioId, emailDoToken)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	// O token já foi usado, ou o usuário trocou de email depois de recebê-lo
+	if !verificado {
+		respostas.ERRO(w, r, http.StatusConflict, respostas.NovoErro(respostas.CodigoEmailJaVerificado, "email já verificado ou token não corresponde ao email atual"))
+		return
+	}
+
+	respostas.JSON(w, http.StatusNoContent, nil, nil)
+}
+
+// ReenviarVerificacao envia um novo email de verificação. A resposta é sempre 202, para não revelar
+// quais emails estão cadastrados nem quais já foram verificados.
+func (controller Controller) ReenviarVerificacao(w http.ResponseWriter, r *http.Request) {
+	corpoDaRequisicao, erro := io.ReadAll(r.Body)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusUnprocessableEntity, erro)
+		return
+	}
+
+	var requisicao requisicaoReenvio
+	if erro = json.Unmarshal(corpoDaRequisicao, &requisicao); erro != nil {
+		respostas.ERRO(w, r, http.StatusBadRequest, erro)
+		return
+	}
+
+	requisicao.Email = strings.TrimSpace(requisicao.Email)
+	if requisicao.Email == "" {
+		respostas.ERRO(w, r, http.StatusBadRequest, respostas.NovoErro(respostas.CodigoDadosInvalidos, "email obrigatório"))
+		return
+	}
+
+	usuario, erro := controller.usuarios.BuscarPorEmail(requisicao.Email)
+	if erro != nil {
+		respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+		return
+	}
+
+	// O envio é feito fora da requisição: o tempo de resposta não pode indicar se houve envio
+	if usuario.ID != 0 && !usuario.Verificado() {
+		ctx := context.WithoutCancel(r.Context())
+		controller.emSegundoPlano(func() { controller.enviarVerificacao(ctx, usuario.ID, usuario.Email) })
+	}
+
+	respostas.JSON(w, http.StatusAccepted, nil, nil)
+}
+
+// enviarVerificacao envia ao usuário o email com o token de verificação. Falhas são registradas no log
+// e não interrompem a requisição: o usuário pode pedir o reenvio.
+func (controller Controller) enviarVerificacao(ctx context.Context, usuarioId uint64, emailDoUsuario string) {
+	token, erro := autenticacao.CriarTokenDeVerificacao(usuarioId, emailDoUsuario)
+	if erro == nil {
+		erro = controller.mailer.Enviar(email.Mensagem{
+			Para:    emailDoUsuario,
+			Assunto: "Confirme seu email no DevBook",
+			Corpo:   corpoDaVerificacao(token),
+		})
+	}
+
+	if erro != nil {
+		slog.ErrorContext(ctx, "falha ao enviar email de verificação", append(logs.Atributos(ctx),
+			slog.Uint64("destinatarioId", usuarioId),
+			slog.Any("erro", erro),
+		)...)
+	}
+}
+
+// corpoDaVerificacao monta o texto do email de verificação
+func corpoDaVerificacao(token string) string {
+	return fmt.Sprintf("Olá!\n\n%s\n\nO token expira em %s. Se você não criou uma conta no DevBook, ignore este email.\n",
+		instrucaoComToken("confirmar seu email", "/verificar-email", token), config.DuracaoTokenVerificacao)
+}
+
+// instrucaoComToken explica como usar o token enviado por email: com um frontend configurado, o token
+// vai no link da página informada; sem ele, o token é enviado para ser usado diretamente na API
+func instrucaoComToken(acao, pagina, token string) string {
+	if config.UrlFrontend != "" {
+		return fmt.Sprintf("Acesse o link abaixo para %s:\n\n%s%s?token=%s", acao, config.UrlFrontend, pagina, url.QueryEscape(token))
+	}
+
+	return fmt.Sprintf("Use o token abaixo para %s:\n\n%s", acao, token)
+}
+
+type requisicaoVerificacao struct {
+	Token string `json:"token"`
+}
+
+type requisicaoReenvio struct {
+	Email string `json:"email"`
+}
@@ -0,0 +1,28 @@
+// Package email envia as mensagens da API (verificação de email, redefinição de senha).
+// Em produção usa SMTP; sem SMTP_HOST configurado, as mensagens são gravadas em arquivo ou no log,
+// o que basta para o desenvolvimento local.
+package email
+
+import "api/src/config"
+
+// Mensagem é um email em texto puro
+type Mensagem struct {
+	Para    string
+	Assunto string
+	Corpo   string
+}
+
+// Mailer envia mensagens de email
+type Mailer interface {
+	Enviar(mensagem Mensagem) error
+}
+
+// NovoMailer escolhe a implementação conforme a configuração: SMTP quando SMTP_HOST está definido,
+// senão a local (arquivo em EMAIL_ARQUIVO ou, na falta dele, o log)
+func NovoMailer() Mailer {
+	if config.SMTPHost != "" {
+		return NovoSMTP(config.SMTPHost, config.SMTPPorta, config.SMTPUsuario, config.SMTPSenha, config.EmailRemetente)
+	}
+
+	return NovoLocal(config.EmailArquivo)
+}
@@ -0,0 +1,46 @@
+package email
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// Local não envia as mensagens: acrescenta cada uma ao arquivo informado ou, sem arquivo, escreve no log.
+// Destina-se ao desenvolvimento local e aos testes.
+type Local struct {
+	mu      sync.Mutex
+	arquivo string
+}
+
+// NovoLocal cria o mailer local. Com arquivo vazio as mensagens vão para o log.
+func NovoLocal(arquivo string) *Local {
+	return &Local{arquivo: arquivo}
+}
+
+// Enviar grava a mensagem no arquivo ou no log
+func (local *Local) Enviar(mensagem Mensagem) error {
+	if local.arquivo == "" {
+		slog.Info("email não enviado (sem SMTP configurado)",
+			slog.String("para", mensagem.Para),
+			slog.String("assunto", mensagem.Assunto),
+			slog.String("corpo", mensagem.Corpo),
+		)
+		return nil
+	}
+
+	local.mu.Lock()
+	defer local.mu.Unlock()
+
+	arquivo, erro := os.OpenFile(local.arquivo, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if erro != nil {
+		return erro
+	}
+	defer arquivo.Close()
+
+	_, erro = fmt.Fprintf(arquivo, "Data: %s\nPara: %s\nAssunto: %s\n\n%s\n\n----\n\n",
+		time.Now().Format(time.RFC3339), mensagem.Para, mensagem.Assunto, mensagem.Corpo)
+	return erro
+}
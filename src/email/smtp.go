@@ -0,0 +1,61 @@
+package email
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTP envia as mensagens por um servidor SMTP, com autenticação PLAIN quando há usuário configurado.
+// net/smtp usa STARTTLS sempre que o servidor oferece.
+type SMTP struct {
+	endereco  string
+	auth      smtp.Auth
+	remetente string
+}
+
+// NovoSMTP cria o mailer SMTP para o servidor informado
+func NovoSMTP(host string, porta int, usuario, senha, remetente string) *SMTP {
+	var auth smtp.Auth
+	if usuario != "" {
+		auth = smtp.PlainAuth("", usuario, senha, host)
+	}
+
+	return &SMTP{
+		endereco:  net.JoinHostPort(host, strconv.Itoa(porta)),
+		auth:      auth,
+		remetente: remetente,
+	}
+}
+
+// Enviar entrega a mensagem ao servidor SMTP. O remetente pode incluir nome ("DevBook <no-reply@devbook.com>"):
+// o envelope (MAIL FROM) recebe somente o endereço, e o cabeçalho From a forma completa.
+func (servidor *SMTP) Enviar(mensagem Mensagem) error {
+	if strings.ContainsAny(mensagem.Para, "\r\n") || strings.ContainsAny(mensagem.Assunto, "\r\n") {
+		return errors.New("cabeçalho de email inválido")
+	}
+
+	remetente, erro := mail.ParseAddress(servidor.remetente)
+	if erro != nil {
+		return fmt.Errorf("remetente de email inválido: %w", erro)
+	}
+
+	conteudo := strings.Join([]string{
+		"From: " + remetente.String(),
+		"To: " + mensagem.Para,
+		"Subject: " + mime.QEncoding.Encode("utf-8", mensagem.Assunto),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		mensagem.Corpo,
+	}, "\r\n")
+
+	return smtp.SendMail(servidor.endereco, servidor.auth, remetente.Address, []string{mensagem.Para}, []byte(conteudo))
+}
@@ -0,0 +1,149 @@
+// Package limitador controla tentativas e taxas de requisições. O estado fica em um Armazenamento,
+// que é em memória por padrão e pode ser trocado por um compartilhado entre instâncias da API.
+package limitador
+
+import (
+	"sync"
+	"time"
+)
+
+// Armazenamento guarda contadores de falhas, bloqueios temporários e baldes de fichas, identificados por chave
+type Armazenamento interface {
+	// SomarFalha incrementa o contador da chave e retorna o total dentro da janela.
+	// A janela começa na primeira falha; ao fim dela o contador volta a zero.
+	SomarFalha(chave string, janela time.Duration) (uint64, error)
+	// Bloquear impede a chave até o instante informado
+	Bloquear(chave string, ate time.Time) error
+	// BloqueadaAte retorna o fim do bloqueio da chave, ou o instante zero quando ela não está bloqueada
+	BloqueadaAte(chave string) (time.Time, error)
+	// Limpar descarta as falhas e o bloqueio da chave
+	Limpar(chave string) error
+	// Consumir retira uma ficha do balde da chave, criado cheio na primeira vez, segundo o limite informado
+	Consumir(chave string, limite Limite) (Consumo, error)
+}
+
+// intervaloDeLimpeza é a quantidade de escritas entre duas varreduras das chaves expiradas
+const intervaloDeLimpeza = 1000
+
+type falhas struct {
+	total    uint64
+	expiraEm time.Time
+}
+
+// Memoria é o Armazenamento em memória, local a cada instância da API
+type Memoria struct {
+	mu        sync.Mutex
+	falhas    map[string]falhas
+	bloqueios map[string]time.Time
+	baldes    map[string]*balde
+	escritas  int
+}
+
+// NovaMemoria cria um armazenamento em memória vazio
+func NovaMemoria() *Memoria {
+	return &Memoria{
+		falhas:    make(map[string]falhas),
+		bloqueios: make(map[string]time.Time),
+		baldes:    make(map[string]*balde),
+	}
+}
+
+// SomarFalha incrementa o contador da chave dentro da janela
+func (memoria *Memoria) SomarFalha(chave string, janela time.Duration) (uint64, error) {
+	memoria.mu.Lock()
+	defer memoria.mu.Unlock()
+
+	agora := time.Now()
+	memoria.limparExpiradas(agora)
+
+	contador, existe := memoria.falhas[chave]
+	if !existe || !agora.Before(contador.expiraEm) {
+		contador = falhas{expiraEm: agora.Add(janela)}
+	}
+
+	contador.total++
+	memoria.falhas[chave] = contador
+
+	return contador.total, nil
+}
+
+// Bloquear impede a chave até o instante informado
+func (memoria *Memoria) Bloquear(chave string, ate time.Time) error {
+	memoria.mu.Lock()
+	defer memoria.mu.Unlock()
+
+	memoria.limparExpiradas(time.Now())
+	memoria.bloqueios[chave] = ate
+
+	return nil
+}
+
+// BloqueadaAte retorna o fim do bloqueio da chave, ou o instante zero quando ela não está bloqueada
+func (memoria *Memoria) BloqueadaAte(chave string) (time.Time, error) {
+	memoria.mu.Lock()
+	defer memoria.mu.Unlock()
+
+	ate, existe := memoria.bloqueios[chave]
+	if !existe || !time.Now().Before(ate) {
+		return time.Time{}, nil
+	}
+
+	return ate, nil
+}
+
+// Limpar descarta as falhas e o bloqueio da chave
+func (memoria *Memoria) Limpar(chave string) error {
+	memoria.mu.Lock()
+	defer memoria.mu.Unlock()
+
+	delete(memoria.falhas, chave)
+	delete(memoria.bloqueios, chave)
+
+	return nil
+}
+
+// Consumir retira uma ficha do balde da chave
+func (memoria *Memoria) Consumir(chave string, limite Limite) (Consumo, error) {
+	memoria.mu.Lock()
+	defer memoria.mu.Unlock()
+
+	agora := time.Now()
+	memoria.limparExpiradas(agora)
+
+	baldeDaChave, existe := memoria.baldes[chave]
+	if !existe {
+		baldeDaChave = &balde{fichas: float64(limite.Requisicoes), atualizadoEm: agora}
+		memoria.baldes[chave] = baldeDaChave
+	}
+
+	return baldeDaChave.consumir(limite, agora), nil
+}
+
+// limparExpiradas remove, de tempos em tempos, as chaves vencidas, para que a memória usada
+// não cresça com cada IP ou email já visto. Deve ser chamado com o mutex travado.
+func (memoria *Memoria) limparExpiradas(agora time.Time) {
+	memoria.escritas++
+	if memoria.escritas < intervaloDeLimpeza {
+		return
+	}
+	memoria.escritas = 0
+
+	for chave, contador := range memoria.falhas {
+		if !agora.Before(contador.expiraEm) {
+			delete(memoria.falhas, chave)
+		}
+	}
+
+	for chave, ate := range memoria.bloqueios {
+		if !agora.Before(ate) {
+			delete(memoria.bloqueios, chave)
+		}
+	}
+
+	// Um balde cheio equivale a um balde novo
+	for chave, baldeDaChave := range memoria.baldes {
+		if !agora.Before(baldeDaChave.cheioEm) {
+			delete(memoria.baldes, chave)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package limitador
+
+import (
+	"api/src/config"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// IPDoCliente retorna o IP de quem fez a requisição. Atrás de um proxy reverso (API_CONFIAR_PROXY)
+// usa o último endereço de X-Forwarded-For, que é o acrescentado pelo próprio proxy;
+// os anteriores são informados pelo cliente e não são confiáveis.
+func IPDoCliente(r *http.Request) string {
+	if config.ConfiarEmProxy {
+		if valores := r.Header.Values("X-Forwarded-For"); len(valores) > 0 {
+			enderecos := strings.Split(valores[len(valores)-1], ",")
+			if ip := strings.TrimSpace(enderecos[len(enderecos)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+
+	ip, _, erro := net.SplitHostPort(r.RemoteAddr)
+	if erro != nil {
+		return r.RemoteAddr
+	}
+
+	return ip
+}
@@ -0,0 +1,75 @@
+package limitador
+
+import (
+	"api/src/config"
+	"strings"
+	"time"
+)
+
+// TentativasDeLogin limita as tentativas de login por IP e por conta. Ao atingir o máximo de falhas
+// dentro da janela configurada, o IP ou a conta ficam bloqueados temporariamente.
+// Emails inexistentes também são contados, para que o bloqueio não revele quais contas existem.
+type TentativasDeLogin struct {
+	armazenamento Armazenamento
+}
+
+// NovasTentativasDeLogin cria o controle de tentativas de login sobre o armazenamento informado
+func NovasTentativasDeLogin(armazenamento Armazenamento) *TentativasDeLogin {
+	return &TentativasDeLogin{armazenamento}
+}
+
+// Verificar retorna quanto tempo falta para o IP ou a conta poderem tentar novamente.
+// Zero indica que a tentativa é permitida.
+func (tentativas *TentativasDeLogin) Verificar(ip, email string) (time.Duration, error) {
+	var espera time.Duration
+
+	for _, chave := range []string{chaveDoIP(ip), chaveDaConta(email)} {
+		ate, erro := tentativas.armazenamento.BloqueadaAte(chave)
+		if erro != nil {
+			return 0, erro
+		}
+
+		if restante := time.Until(ate); restante > espera {
+			espera = restante
+		}
+	}
+
+	return espera, nil
+}
+
+// RegistrarFalha conta a falha para o IP e para a conta, bloqueando o que atingir o máximo de falhas
+func (tentativas *TentativasDeLogin) RegistrarFalha(ip, email string) error {
+	limites := map[string]uint64{
+		chaveDoIP(ip):       config.LoginMaxFalhasPorIP,
+		chaveDaConta(email): config.LoginMaxFalhasPorConta,
+	}
+
+	for chave, maximo := range limites {
+		total, erro := tentativas.armazenamento.SomarFalha(chave, config.LoginJanelaFalhas)
+		if erro != nil {
+			return erro
+		}
+
+		if total >= maximo {
+			if erro = tentativas.armazenamento.Bloquear(chave, time.Now().Add(config.LoginTempoBloqueio)); erro != nil {
+				return erro
+			}
+		}
+	}
+
+	return nil
+}
+
+// RegistrarSucesso zera as falhas da conta. As do IP continuam valendo, para que quem testa
+// senhas de várias contas não se livre do limite entrando na própria.
+func (tentativas *TentativasDeLogin) RegistrarSucesso(email string) error {
+	return tentativas.armazenamento.Limpar(chaveDaConta(email))
+}
+
+func chaveDoIP(ip string) string {
+	return "login:ip:" + ip
+}
+
+func chaveDaConta(email string) string {
+	return "login:conta:" + strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,65 @@
+package limitador
+
+import (
+	"math"
+	"time"
+)
+
+// Limite é a taxa permitida em uma rota: Requisicoes a cada Periodo. Funciona como um balde de fichas
+// (token bucket) com capacidade Requisicoes, reabastecido continuamente ao longo do Periodo,
+// o que permite rajadas curtas sem ultrapassar a taxa média. O valor zero significa sem limite.
+type Limite struct {
+	Requisicoes uint64
+	Periodo     time.Duration
+}
+
+// Ativo indica se o limite deve ser aplicado
+func (limite Limite) Ativo() bool {
+	return limite.Requisicoes > 0 && limite.Periodo > 0
+}
+
+// Consumo é o resultado de uma tentativa de consumir uma ficha do balde
+type Consumo struct {
+	// Permitido indica se havia ficha disponível para a requisição
+	Permitido bool
+	// Restantes é a quantidade de fichas que sobraram no balde
+	Restantes uint64
+	// Reinicio é quanto falta para o balde voltar a ficar cheio
+	Reinicio time.Duration
+	// Espera é quanto falta para a próxima ficha, quando a requisição não foi permitida
+	Espera time.Duration
+}
+
+// balde guarda as fichas disponíveis de uma chave e quando foram calculadas pela última vez
+type balde struct {
+	fichas       float64
+	atualizadoEm time.Time
+	cheioEm      time.Time
+}
+
+// consumir reabastece o balde pelo tempo decorrido e retira uma ficha, se houver
+func (balde *balde) consumir(limite Limite, agora time.Time) Consumo {
+	capacidade := float64(limite.Requisicoes)
+	porSegundo := capacidade / limite.Periodo.Seconds()
+
+	balde.fichas = math.Min(capacidade, balde.fichas+agora.Sub(balde.atualizadoEm).Seconds()*porSegundo)
+	balde.atualizadoEm = agora
+
+	var consumo Consumo
+	if balde.fichas >= 1 {
+		balde.fichas--
+		consumo.Permitido = true
+	} else {
+		consumo.Espera = duracaoEmSegundos((1 - balde.fichas) / porSegundo)
+	}
+
+	consumo.Restantes = uint64(balde.fichas)
+	consumo.Reinicio = duracaoEmSegundos((capacidade - balde.fichas) / porSegundo)
+	balde.cheioEm = agora.Add(consumo.Reinicio)
+
+	return consumo
+}
+
+func duracaoEmSegundos(segundos float64) time.Duration {
+	return time.Duration(segundos * float64(time.Second))
+}
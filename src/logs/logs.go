@@ -0,0 +1,76 @@
+package logs
+
+import (
+	"api/src/config"
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// valorOmitido substitui, nos logs, o valor de atributos sensíveis
+const valorOmitido = "[omitido]"
+
+// atributosSensiveis são as chaves (em minúsculas) cujo valor nunca deve ser escrito nos logs
+var atributosSensiveis = map[string]bool{
+	"senha":         true,
+	"token":         true,
+	"refreshtoken":  true,
+	"authorization": true,
+	"secret_key":    true,
+	"secretkey":     true,
+}
+
+// Configurar define o logger padrão da aplicação: saída JSON no stdout, com o nível definido em LOG_NIVEL
+func Configurar() {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		Level:       config.NivelLog,
+		ReplaceAttr: omitirSensiveis,
+	}))
+
+	slog.SetDefault(logger)
+}
+
+func omitirSensiveis(_ []string, atributo slog.Attr) slog.Attr {
+	if atributosSensiveis[strings.ToLower(atributo.Key)] {
+		return slog.String(atributo.Key, valorOmitido)
+	}
+
+	return atributo
+}
+
+// Requisicao guarda dados da requisição em andamento que devem constar nos logs.
+// É criada pelo middleware de log e completada pelos middlewares seguintes (ex.: usuário autenticado).
+type Requisicao struct {
+	Id        string
+	UsuarioId uint64
+}
+
+// chaveRequisicao é a chave da Requisicao no contexto. Tipo não exportado para evitar colisões.
+type chaveRequisicao struct{}
+
+// NoContexto retorna uma cópia do contexto contendo os dados da requisição
+func NoContexto(ctx context.Context, requisicao *Requisicao) context.Context {
+	return context.WithValue(ctx, chaveRequisicao{}, requisicao)
+}
+
+// DoContexto retorna os dados da requisição guardados no contexto, ou nil quando ausentes
+func DoContexto(ctx context.Context) *Requisicao {
+	requisicao, _ := ctx.Value(chaveRequisicao{}).(*Requisicao)
+	return requisicao
+}
+
+// Atributos retorna os atributos de log que identificam a requisição do contexto
+func Atributos(ctx context.Context) []any {
+	requisicao := DoContexto(ctx)
+	if requisicao == nil {
+		return nil
+	}
+
+	atributos := []any{slog.String("requisicaoId", requisicao.Id)}
+	if requisicao.UsuarioId != 0 {
+		atributos = append(atributos, slog.Uint64("usuarioId", requisicao.UsuarioId))
+	}
+
+	return atributos
+}
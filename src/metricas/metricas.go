@@ -0,0 +1,99 @@
+package metricas
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// registro reúne as métricas expostas em /metrics. Usa um registro próprio, e não o global
+// do Prometheus, para que somente as métricas declaradas aqui sejam publicadas.
+var registro = prometheus.NewRegistry()
+
+var (
+	requisicoes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requisicoes_total",
+		Help: "Requisições atendidas, por método, rota (template da URI) e status.",
+	}, []string{"metodo", "rota", "status"})
+
+	duracaoRequisicoes = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_requisicao_duracao_segundos",
+		Help:    "Latência das requisições, por método e rota (template da URI).",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"metodo", "rota"})
+
+	logins = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "devbook_logins_total",
+		Help: "Logins realizados com sucesso.",
+	})
+
+	loginsFalhos = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "devbook_logins_falhos_total",
+		Help: "Tentativas de login recusadas por credenciais inválidas.",
+	})
+
+	publicacoesCriadas = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "devbook_publicacoes_criadas_total",
+		Help: "Publicações criadas.",
+	})
+
+	usuariosSeguidos = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "devbook_usuarios_seguidos_total",
+		Help: "Usuários seguidos.",
+	})
+)
+
+func init() {
+	registro.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		requisicoes,
+		duracaoRequisicoes,
+		logins,
+		loginsFalhos,
+		publicacoesCriadas,
+		usuariosSeguidos,
+	)
+}
+
+// RegistrarBanco publica as estatísticas do pool de conexões (sql.DBStats) com o rótulo db_name="devbook"
+func RegistrarBanco(db *sql.DB) {
+	registro.MustRegister(collectors.NewDBStatsCollector(db, "devbook"))
+}
+
+// Handler atende o endpoint /metrics no formato de exposição do Prometheus
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registro, promhttp.HandlerOpts{})
+}
+
+// RegistrarRequisicao contabiliza a requisição atendida. A rota deve ser o template da URI
+// (ex.: /usuarios/{usuarioId}), nunca o caminho bruto, para manter a cardinalidade limitada.
+func RegistrarRequisicao(metodo, rota string, status int, duracao time.Duration) {
+	requisicoes.WithLabelValues(metodo, rota, strconv.Itoa(status)).Inc()
+	duracaoRequisicoes.WithLabelValues(metodo, rota).Observe(duracao.Seconds())
+}
+
+// LoginRealizado contabiliza um login bem-sucedido
+func LoginRealizado() {
+	logins.Inc()
+}
+
+// LoginFalhou contabiliza uma tentativa de login com credenciais inválidas
+func LoginFalhou() {
+	loginsFalhos.Inc()
+}
+
+// PublicacaoCriada contabiliza uma nova publicação
+func PublicacaoCriada() {
+	publicacoesCriadas.Inc()
+}
+
+// UsuarioSeguido contabiliza um novo vínculo de seguidor
+func UsuarioSeguido() {
+	usuariosSeguidos.Inc()
+}
@@ -2,27 +2,184 @@ package middlewares
 
 import (
 	"api/src/autenticacao"
+	"api/src/limitador"
+	"api/src/logs"
+	"api/src/metricas"
+	"api/src/repositorios"
 	"api/src/respostas"
-	"fmt"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 )
 
-// Logger escreve informações da requisição no terminal
+// Logger registra, em log estruturado, cada requisição atendida: id da requisição, usuário autenticado,
+// status, tamanho da resposta e latência. O id é lido do header X-Request-Id ou gerado, e devolvido na resposta.
 func Logger(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		fmt.Printf("%s %s%s", r.Method, r.Host, r.RequestURI)
-		fmt.Println()
-		next(w, r)
+		inicio := time.Now()
+
+		requisicao := &logs.Requisicao{Id: r.Header.Get("X-Request-Id")}
+		if requisicao.Id == "" {
+			requisicao.Id = gerarIdDaRequisicao()
+		}
+		w.Header().Set("X-Request-Id", requisicao.Id)
+
+		ctx := logs.NoContexto(r.Context(), requisicao)
+
+		resposta := &respostaRegistrada{ResponseWriter: w, status: http.StatusOK}
+		next(resposta, r.WithContext(ctx))
+
+		nivel := slog.LevelInfo
+		switch {
+		case resposta.status >= http.StatusInternalServerError:
+			nivel = slog.LevelError
+		case resposta.status >= http.StatusBadRequest:
+			nivel = slog.LevelWarn
+		}
+
+		atributos := append(logs.Atributos(ctx),
+			slog.String("metodo", r.Method),
+			slog.String("caminho", r.URL.Path),
+			slog.String("host", r.Host),
+			slog.Int("status", resposta.status),
+			slog.Int("tamanho", resposta.tamanho),
+			slog.Float64("latenciaMs", float64(time.Since(inicio).Microseconds())/1000),
+		)
+		slog.Log(ctx, nivel, "requisição atendida", atributos...)
+	}
+}
+
+// Metricas contabiliza cada requisição atendida (contagem por status e latência) sob o template
+// da rota informado, e não sob o caminho da requisição, que contém ids
+func Metricas(rota string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		inicio := time.Now()
+
+		resposta := &respostaRegistrada{ResponseWriter: w, status: http.StatusOK}
+		next(resposta, r)
+
+		metricas.RegistrarRequisicao(r.Method, rota, resposta.status, time.Since(inicio))
+	}
+}
+
+// respostaRegistrada envolve o ResponseWriter para capturar status e tamanho da resposta
+type respostaRegistrada struct {
+	http.ResponseWriter
+	status  int
+	tamanho int
+}
+
+func (resposta *respostaRegistrada) WriteHeader(status int) {
+	resposta.status = status
+	resposta.ResponseWriter.WriteHeader(status)
+}
+
+func (resposta *respostaRegistrada) Write(dados []byte) (int, error) {
+	tamanho, erro := resposta.ResponseWriter.Write(dados)
+	resposta.tamanho += tamanho
+	return tamanho, erro
+}
+
+func gerarIdDaRequisicao() string {
+	bytes := make([]byte, 8)
+	if _, erro := rand.Read(bytes); erro != nil {
+		return ""
 	}
+
+	return hex.EncodeToString(bytes)
 }
 
 // Autenticar verifica se usuário fazendo a requisição está autenticado
-func Autenticar(next http.HandlerFunc) http.HandlerFunc {
+// e se o token utilizado não foi revogado (logout ou troca de senha)
+func Autenticar(next http.HandlerFunc, tokens repositorios.RepositorioDeTokens) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if erro := autenticacao.ValidarToken(r); erro != nil {
-			respostas.ERRO(w, http.StatusUnauthorized, erro)
+		permissoes, erro := autenticacao.ValidarToken(r)
+		if erro != nil {
+			respostas.ERRO(w, r, http.StatusUnauthorized, respostas.NovoErro(respostas.CodigoTokenInvalido, "token inválido ou expirado"))
 			return
 		}
+
+		revogado, erro := tokens.AcessoRevogado(permissoes.Id)
+		if erro != nil {
+			respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+			return
+		}
+
+		if revogado {
+			respostas.ERRO(w, r, http.StatusUnauthorized, respostas.NovoErro(respostas.CodigoTokenRevogado, "token revogado"))
+			return
+		}
+
+		if requisicao := logs.DoContexto(r.Context()); requisicao != nil {
+			requisicao.UsuarioId = permissoes.UsuarioId
+		}
+
+		next(w, r.WithContext(autenticacao.ComPermissoes(r.Context(), permissoes)))
+	}
+}
+
+// RequerPapel restringe a rota aos usuários com o papel informado. Deve ser aplicado depois de Autenticar,
+// pois lê o usuário das permissões guardadas no contexto da requisição. O papel é lido do banco, e não
+// do token, para que um administrador rebaixado perca o acesso sem esperar o token expirar.
+func RequerPapel(papel string, next http.HandlerFunc, usuarios repositorios.RepositorioDeUsuarios) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		usuarioId, erro := autenticacao.ExtrairUsuarioId(r)
+		if erro != nil {
+			respostas.ERRO(w, r, http.StatusUnauthorized, respostas.NovoErro(respostas.CodigoNaoAutenticado, "requisição não autenticada"))
+			return
+		}
+
+		usuario, erro := usuarios.BuscarSituacao(usuarioId)
+		if erro != nil {
+			respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+			return
+		}
+
+		if usuario.Papel != papel {
+			respostas.ERRO(w, r, http.StatusForbidden, respostas.NovoErro(respostas.CodigoPapelInsuficiente, "acesso restrito"))
+			return
+		}
+
 		next(w, r)
 	}
-}
\ No newline at end of file
+}
+
+// LimitarTaxa aplica o limite de requisições da rota, contado por usuário autenticado ou, nas rotas
+// públicas, por IP. Deve ser aplicado depois de Autenticar, pois lê o usuário do contexto da requisição.
+// As respostas trazem os headers X-RateLimit-Limit, X-RateLimit-Remaining e X-RateLimit-Reset (segundos
+// até o limite ser totalmente restabelecido); acima do limite a resposta é 429 com Retry-After.
+func LimitarTaxa(rota string, limite limitador.Limite, armazenamento limitador.Armazenamento, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		chave := "taxa:" + r.Method + " " + rota + ":ip:" + limitador.IPDoCliente(r)
+		if usuarioId, erro := autenticacao.ExtrairUsuarioId(r); erro == nil {
+			chave = "taxa:" + r.Method + " " + rota + ":usuario:" + strconv.FormatUint(usuarioId, 10)
+		}
+
+		consumo, erro := armazenamento.Consumir(chave, limite)
+		if erro != nil {
+			respostas.ERRO(w, r, http.StatusInternalServerError, erro)
+			return
+		}
+
+		w.Header().Set("X-RateLimit-Limit", strconv.FormatUint(limite.Requisicoes, 10))
+		w.Header().Set("X-RateLimit-Remaining", strconv.FormatUint(consumo.Restantes, 10))
+		w.Header().Set("X-RateLimit-Reset", segundosArredondados(consumo.Reinicio))
+
+		if !consumo.Permitido {
+			w.Header().Set("Retry-After", segundosArredondados(consumo.Espera))
+			respostas.ERRO(w, r, http.StatusTooManyRequests, respostas.NovoErro(respostas.CodigoLimiteExcedido, "limite de requisições excedido, tente novamente mais tarde"))
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// segundosArredondados formata a duração em segundos inteiros, arredondando para cima
+func segundosArredondados(duracao time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(duracao.Seconds())))
+}
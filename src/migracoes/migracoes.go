@@ -0,0 +1,304 @@
+package migracoes
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Os arquivos seguem o padrão NNNN_nome.up.sql / NNNN_nome.down.sql e são aplicados em ordem de versão
+//
+//go:embed sql/*.sql
+var arquivos embed.FS
+
+// Migracao representa uma alteração versionada do esquema do banco
+type Migracao struct {
+	Versao uint64
+	Nome   string
+	up     string
+	down   string
+}
+
+// Situacao indica se uma migração já foi aplicada ao banco
+type Situacao struct {
+	Migracao
+	AplicadaEm *time.Time
+}
+
+// Carregar lê as migrações embutidas no binário, ordenadas por versão
+func Carregar() ([]Migracao, error) {
+	entradas, erro := fs.ReadDir(arquivos, "sql")
+	if erro != nil {
+		return nil, erro
+	}
+
+	porVersao := make(map[uint64]*Migracao)
+
+	for _, entrada := range entradas {
+		nomeArquivo := entrada.Name()
+
+		var direcao string
+		switch {
+		case strings.HasSuffix(nomeArquivo, ".up.sql"):
+			direcao = "up"
+		case strings.HasSuffix(nomeArquivo, ".down.sql"):
+			direcao = "down"
+		default:
+			return nil, fmt.Errorf("migração com nome inválido: %s", nomeArquivo)
+		}
+
+		partes := strings.SplitN(strings.TrimSuffix(nomeArquivo, "."+direcao+".sql"), "_", 2)
+		if len(partes) != 2 {
+			return nil, fmt.Errorf("migração com nome inválido: %s", nomeArquivo)
+		}
+
+		versao, erro := strconv.ParseUint(partes[0], 10, 64)
+		if erro != nil {
+			return nil, fmt.Errorf("migração com versão inválida: %s", nomeArquivo)
+		}
+
+		conteudo, erro := arquivos.ReadFile(path.Join("sql", nomeArquivo))
+		if erro != nil {
+			return nil, erro
+		}
+
+		migracao, existe := porVersao[versao]
+		if !existe {
+			migracao = &Migracao{Versao: versao, Nome: partes[1]}
+			porVersao[versao] = migracao
+		}
+
+		if direcao == "up" {
+			migracao.up = string(conteudo)
+		} else {
+			migracao.down = string(conteudo)
+		}
+	}
+
+	migracoes := make([]Migracao, 0, len(porVersao))
+	for _, migracao := range porVersao {
+		if migracao.up == "" {
+			return nil, fmt.Errorf("migração %04d sem arquivo up", migracao.Versao)
+		}
+		migracoes = append(migracoes, *migracao)
+	}
+
+	sort.Slice(migracoes, func(i, j int) bool {
+		return migracoes[i].Versao < migracoes[j].Versao
+	})
+
+	return migracoes, nil
+}
+
+// Consultar retorna todas as migrações conhecidas e se cada uma já foi aplicada
+func Consultar(db *sql.DB) ([]Situacao, error) {
+	migracoes, erro := Carregar()
+	if erro != nil {
+		return nil, erro
+	}
+
+	aplicadas, erro := buscarAplicadas(db)
+	if erro != nil {
+		return nil, erro
+	}
+
+	situacoes := make([]Situacao, 0, len(migracoes))
+	for _, migracao := range migracoes {
+		situacao := Situacao{Migracao: migracao}
+		if aplicadaEm, ok := aplicadas[migracao.Versao]; ok {
+			situacao.AplicadaEm = &aplicadaEm
+		}
+		situacoes = append(situacoes, situacao)
+	}
+
+	return situacoes, nil
+}
+
+// Pendentes retorna as migrações ainda não aplicadas ao banco
+func Pendentes(db *sql.DB) ([]Migracao, error) {
+	situacoes, erro := Consultar(db)
+	if erro != nil {
+		return nil, erro
+	}
+
+	pendentes := make([]Migracao, 0)
+	for _, situacao := range situacoes {
+		if situacao.AplicadaEm == nil {
+			pendentes = append(pendentes, situacao.Migracao)
+		}
+	}
+
+	return pendentes, nil
+}
+
+// ContarPendentes retorna quantas migrações ainda não foram aplicadas ao banco. Ao contrário de Pendentes,
+// apenas lê o banco (sem criar a tabela schema_migrations) e respeita o prazo do contexto, podendo ser
+// usada a cada verificação de prontidão. Sem a tabela, todas as migrações estão pendentes.
+func ContarPendentes(ctx context.Context, db *sql.DB) (int, error) {
+	migracoes, erro := Carregar()
+	if erro != nil {
+		return 0, erro
+	}
+
+	var tabelas int
+	if erro = db.QueryRowContext(ctx,
+		"select count(*) from information_schema.tables where table_schema = database() and table_name = 'schema_migrations'",
+	).Scan(&tabelas); erro != nil {
+		return 0, erro
+	}
+	if tabelas == 0 {
+		return len(migracoes), nil
+	}
+
+	linhas, erro := db.QueryContext(ctx, "select versao from schema_migrations")
+	if erro != nil {
+		return 0, erro
+	}
+	defer linhas.Close()
+
+	aplicadas := make(map[uint64]bool)
+	for linhas.Next() {
+		var versao uint64
+		if erro = linhas.Scan(&versao); erro != nil {
+			return 0, erro
+		}
+		aplicadas[versao] = true
+	}
+	if erro = linhas.Err(); erro != nil {
+		return 0, erro
+	}
+
+	pendentes := 0
+	for _, migracao := range migracoes {
+		if !aplicadas[migracao.Versao] {
+			pendentes++
+		}
+	}
+
+	return pendentes, nil
+}
+
+// Aplicar executa, em ordem, todas as migrações pendentes e retorna as que foram aplicadas
+func Aplicar(db *sql.DB) ([]Migracao, error) {
+	pendentes, erro := Pendentes(db)
+	if erro != nil {
+		return nil, erro
+	}
+
+	for i, migracao := range pendentes {
+		if erro = executar(db, migracao.up); erro != nil {
+			return pendentes[:i], fmt.Errorf("migração %04d_%s: %w", migracao.Versao, migracao.Nome, erro)
+		}
+
+		if _, erro = db.Exec(
+			"insert into schema_migrations (versao, nome) values (?, ?)", migracao.Versao, migracao.Nome,
+		); erro != nil {
+			return pendentes[:i], erro
+		}
+	}
+
+	return pendentes, nil
+}
+
+// Reverter desfaz a última migração aplicada. Retorna nil quando não há migração a reverter.
+func Reverter(db *sql.DB) (*Migracao, error) {
+	situacoes, erro := Consultar(db)
+	if erro != nil {
+		return nil, erro
+	}
+
+	for i := len(situacoes) - 1; i >= 0; i-- {
+		if situacoes[i].AplicadaEm == nil {
+			continue
+		}
+
+		migracao := situacoes[i].Migracao
+		if migracao.down == "" {
+			return nil, fmt.Errorf("migração %04d_%s não possui arquivo down", migracao.Versao, migracao.Nome)
+		}
+
+		if erro = executar(db, migracao.down); erro != nil {
+			return nil, fmt.Errorf("migração %04d_%s: %w", migracao.Versao, migracao.Nome, erro)
+		}
+
+		if _, erro = db.Exec("delete from schema_migrations where versao = ?", migracao.Versao); erro != nil {
+			return nil, erro
+		}
+
+		return &migracao, nil
+	}
+
+	return nil, nil
+}
+
+func buscarAplicadas(db *sql.DB) (map[uint64]time.Time, error) {
+	if _, erro := db.Exec(
+		`CREATE TABLE IF NOT EXISTS schema_migrations (
+			versao bigint unsigned primary key,
+			nome varchar(100) not null,
+			aplicadaEm timestamp default current_timestamp
+		) ENGINE=INNODB`,
+	); erro != nil {
+		return nil, erro
+	}
+
+	linhas, erro := db.Query("select versao, aplicadaEm from schema_migrations")
+	if erro != nil {
+		return nil, erro
+	}
+	defer linhas.Close()
+
+	aplicadas := make(map[uint64]time.Time)
+
+	for linhas.Next() {
+		var versao uint64
+		var aplicadaEm time.Time
+
+		if erro = linhas.Scan(&versao, &aplicadaEm); erro != nil {
+			return nil, erro
+		}
+
+		aplicadas[versao] = aplicadaEm
+	}
+
+	return aplicadas, linhas.Err()
+}
+
+// executar roda, um a um, os comandos do arquivo de migração.
+// O driver do MySQL não executa múltiplos comandos em um único Exec sem multiStatements.
+func executar(db *sql.DB, script string) error {
+	for _, comando := range separarComandos(script) {
+		if _, erro := db.Exec(comando); erro != nil {
+			return erro
+		}
+	}
+
+	return nil
+}
+
+func separarComandos(script string) []string {
+	var semComentarios strings.Builder
+	for _, linha := range strings.Split(script, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(linha), "--") {
+			continue
+		}
+		semComentarios.WriteString(linha)
+		semComentarios.WriteString("\n")
+	}
+
+	comandos := make([]string, 0)
+	for _, comando := range strings.Split(semComentarios.String(), ";") {
+		if comando = strings.TrimSpace(comando); comando != "" {
+			comandos = append(comandos, comando)
+		}
+	}
+
+	return comandos
+}
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS publicacoes;
+
+DROP TABLE IF EXISTS seguidores;
+
+DROP TABLE IF EXISTS usuarios;
@@ -1,12 +1,5 @@
--- CREATE DATABASE IF NOT EXISTS devbook;
--- USE devbook;
-
-DROP TABLE IF EXISTS publicacoes;
-
-DROP TABLE IF EXISTS seguidores;
-
-DROP TABLE IF EXISTS usuarios;
-
+-- Esquema original da API. Usa IF NOT EXISTS para que bases criadas antes das migrações
+-- (pelo antigo sql/sql.sql) sejam adotadas sem perda de dados.
 CREATE TABLE IF NOT EXISTS usuarios (
     id int auto_increment primary key,
     nome varchar(50) not null,
@@ -16,8 +9,6 @@ CREATE TABLE IF NOT EXISTS usuarios (
     criadoEm timestamp default current_timestamp()
 ) ENGINE=INNODB;
 
-DROP TABLE IF EXISTS seguidores;
-
 CREATE TABLE IF NOT EXISTS seguidores (
     usuario_id int not null,
     FOREIGN KEY (usuario_id) REFERENCES usuarios(id) ON DELETE CASCADE,
@@ -35,4 +26,3 @@ CREATE TABLE IF NOT EXISTS publicacoes (
     curtidas int default 0,
     criadaEm timestamp default current_timestamp
 ) ENGINE=INNODB;
-
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS curtidas (
+    usuario_id int not null,
+    FOREIGN KEY (usuario_id) REFERENCES usuarios(id) ON DELETE CASCADE,
+    publicacao_id int not null,
+    FOREIGN KEY (publicacao_id) REFERENCES publicacoes(id) ON DELETE CASCADE,
+    criadaEm timestamp default current_timestamp,
+    PRIMARY KEY(usuario_id, publicacao_id)
+) ENGINE=INNODB;
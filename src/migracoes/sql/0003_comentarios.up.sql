@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS comentarios (
+    id int auto_increment primary key,
+    publicacao_id int not null,
+    FOREIGN KEY (publicacao_id) REFERENCES publicacoes(id) ON DELETE CASCADE,
+    autor_id int not null,
+    FOREIGN KEY (autor_id) REFERENCES usuarios(id) ON DELETE CASCADE,
+    conteudo varchar(500) not null,
+    criadoEm timestamp default current_timestamp
+) ENGINE=INNODB;
@@ -0,0 +1,5 @@
+DROP INDEX idx_usuarios_criado_em ON usuarios;
+
+DROP INDEX idx_publicacoes_criada_em ON publicacoes;
+
+ALTER TABLE seguidores DROP COLUMN criadoEm;
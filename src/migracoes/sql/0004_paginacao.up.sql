@@ -0,0 +1,7 @@
+-- Data em que o usuário passou a seguir, usada na paginação de seguidores/seguindo
+ALTER TABLE seguidores ADD COLUMN criadoEm timestamp default current_timestamp;
+
+-- Índices para a paginação por (data, id)
+CREATE INDEX idx_publicacoes_criada_em ON publicacoes (criadaEm, id);
+
+CREATE INDEX idx_usuarios_criado_em ON usuarios (criadoEm, id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS tokens_revogados;
+
+DROP TABLE IF EXISTS refresh_tokens;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id int auto_increment primary key,
+    usuario_id int not null,
+    FOREIGN KEY (usuario_id) REFERENCES usuarios(id) ON DELETE CASCADE,
+    token_hash char(64) not null unique,
+    acesso_jti varchar(64) not null,
+    expiraEm timestamp not null,
+    revogadoEm timestamp null default null,
+    criadoEm timestamp default current_timestamp
+) ENGINE=INNODB;
+
+CREATE TABLE IF NOT EXISTS tokens_revogados (
+    jti varchar(64) primary key,
+    expiraEm timestamp not null
+) ENGINE=INNODB;
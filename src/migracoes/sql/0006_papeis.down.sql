@@ -0,0 +1,3 @@
+ALTER TABLE usuarios
+    DROP COLUMN suspensoEm,
+    DROP COLUMN papel;
@@ -0,0 +1,4 @@
+-- Papel do usuário (usuario ou admin) e suspensão de contas pela moderação
+ALTER TABLE usuarios
+    ADD COLUMN papel varchar(20) not null default 'usuario',
+    ADD COLUMN suspensoEm timestamp null default null;
@@ -0,0 +1,3 @@
+ALTER TABLE publicacoes DROP COLUMN ocultaEm;
+
+DROP TABLE IF EXISTS denuncias;
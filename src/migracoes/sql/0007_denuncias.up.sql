@@ -0,0 +1,23 @@
+-- Denúncias de publicações e de usuários. Cada usuário denuncia uma mesma publicação/usuário uma única vez.
+CREATE TABLE IF NOT EXISTS denuncias (
+    id int auto_increment primary key,
+    denunciante_id int not null,
+    FOREIGN KEY (denunciante_id) REFERENCES usuarios(id) ON DELETE CASCADE,
+    publicacao_id int null,
+    FOREIGN KEY (publicacao_id) REFERENCES publicacoes(id) ON DELETE CASCADE,
+    usuario_id int null,
+    FOREIGN KEY (usuario_id) REFERENCES usuarios(id) ON DELETE CASCADE,
+    motivo varchar(30) not null,
+    descricao varchar(500) not null default '',
+    situacao varchar(20) not null default 'pendente',
+    moderador_id int null,
+    FOREIGN KEY (moderador_id) REFERENCES usuarios(id) ON DELETE SET NULL,
+    moderadaEm timestamp null default null,
+    criadaEm timestamp default current_timestamp,
+    UNIQUE KEY uk_denuncias_publicacao (denunciante_id, publicacao_id),
+    UNIQUE KEY uk_denuncias_usuario (denunciante_id, usuario_id),
+    INDEX idx_denuncias_situacao (situacao, criadaEm, id)
+) ENGINE=INNODB;
+
+-- Publicações ocultadas dos feeds por acumularem denúncias
+ALTER TABLE publicacoes ADD COLUMN ocultaEm timestamp null default null;
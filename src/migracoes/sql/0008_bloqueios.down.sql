@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS silenciados;
+
+DROP TABLE IF EXISTS bloqueios;
@@ -0,0 +1,20 @@
+-- Bloqueios são mútuos: nenhum dos dois usuários vê o perfil ou as publicações do outro, nem pode segui-lo
+CREATE TABLE IF NOT EXISTS bloqueios (
+    usuario_id int not null,
+    FOREIGN KEY (usuario_id) REFERENCES usuarios(id) ON DELETE CASCADE,
+    bloqueado_id int not null,
+    FOREIGN KEY (bloqueado_id) REFERENCES usuarios(id) ON DELETE CASCADE,
+    criadoEm timestamp default current_timestamp,
+    primary key (usuario_id, bloqueado_id),
+    INDEX idx_bloqueios_bloqueado (bloqueado_id, usuario_id)
+) ENGINE=INNODB;
+
+-- Silenciar esconde as publicações do silenciado somente do feed de quem silenciou
+CREATE TABLE IF NOT EXISTS silenciados (
+    usuario_id int not null,
+    FOREIGN KEY (usuario_id) REFERENCES usuarios(id) ON DELETE CASCADE,
+    silenciado_id int not null,
+    FOREIGN KEY (silenciado_id) REFERENCES usuarios(id) ON DELETE CASCADE,
+    criadoEm timestamp default current_timestamp,
+    primary key (usuario_id, silenciado_id)
+) ENGINE=INNODB;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS solicitacoes_seguir;
+
+ALTER TABLE usuarios DROP COLUMN privado;
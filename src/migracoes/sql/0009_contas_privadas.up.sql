@@ -0,0 +1,12 @@
+-- Contas privadas: publicações e seguidores só são visíveis para quem segue a conta
+ALTER TABLE usuarios ADD COLUMN privado boolean not null default false;
+
+-- Pedidos para seguir contas privadas, aguardando aceite do dono da conta
+CREATE TABLE IF NOT EXISTS solicitacoes_seguir (
+    usuario_id int not null,
+    FOREIGN KEY (usuario_id) REFERENCES usuarios(id) ON DELETE CASCADE,
+    solicitante_id int not null,
+    FOREIGN KEY (solicitante_id) REFERENCES usuarios(id) ON DELETE CASCADE,
+    criadaEm timestamp default current_timestamp,
+    primary key (usuario_id, solicitante_id)
+) ENGINE=INNODB;
@@ -0,0 +1 @@
+ALTER TABLE publicacoes DROP INDEX ft_publicacoes_busca;
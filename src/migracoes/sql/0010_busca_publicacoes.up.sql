@@ -0,0 +1,2 @@
+-- Índice usado pela busca textual de publicações (match ... against)
+ALTER TABLE publicacoes ADD FULLTEXT INDEX ft_publicacoes_busca (titulo, conteudo);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS mencoes;
+
+DROP TABLE IF EXISTS publicacoes_hashtags;
@@ -0,0 +1,23 @@
+-- Hashtags extraídas do conteúdo das publicações. criadaEm repete a data da publicação,
+-- permitindo contar as hashtags em alta sem juntar com publicacoes.
+CREATE TABLE IF NOT EXISTS publicacoes_hashtags (
+    publicacao_id int not null,
+    FOREIGN KEY (publicacao_id) REFERENCES publicacoes(id) ON DELETE CASCADE,
+    hashtag varchar(100) not null,
+    criadaEm timestamp default current_timestamp,
+    primary key (publicacao_id, hashtag),
+    INDEX idx_publicacoes_hashtags_hashtag (hashtag, criadaEm),
+    INDEX idx_publicacoes_hashtags_criadaEm (criadaEm)
+) ENGINE=INNODB;
+
+-- Menções (@nick) resolvidas para usuários. inicio e fim são posições em caracteres no conteúdo.
+CREATE TABLE IF NOT EXISTS mencoes (
+    publicacao_id int not null,
+    FOREIGN KEY (publicacao_id) REFERENCES publicacoes(id) ON DELETE CASCADE,
+    usuario_id int not null,
+    FOREIGN KEY (usuario_id) REFERENCES usuarios(id) ON DELETE CASCADE,
+    inicio int not null,
+    fim int not null,
+    primary key (publicacao_id, inicio),
+    INDEX idx_mencoes_usuario (usuario_id)
+) ENGINE=INNODB;
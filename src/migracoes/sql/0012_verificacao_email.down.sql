@@ -0,0 +1 @@
+ALTER TABLE usuarios DROP COLUMN verificadoEm;
@@ -0,0 +1,5 @@
+-- Data em que o usuário confirmou o email; nula enquanto não confirmado.
+-- Contas criadas antes da verificação existir são consideradas verificadas.
+ALTER TABLE usuarios ADD COLUMN verificadoEm timestamp null default null;
+
+UPDATE usuarios SET verificadoEm = criadoEm;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS tokens_redefinicao_senha;
@@ -0,0 +1,10 @@
+-- Tokens de redefinição de senha enviados por email. Como nos refresh tokens, somente o hash é guardado.
+CREATE TABLE IF NOT EXISTS tokens_redefinicao_senha (
+    id int auto_increment primary key,
+    usuario_id int not null,
+    FOREIGN KEY (usuario_id) REFERENCES usuarios(id) ON DELETE CASCADE,
+    token_hash char(64) not null unique,
+    expiraEm timestamp not null,
+    usadoEm timestamp null default null,
+    criadoEm timestamp default current_timestamp
+) ENGINE=INNODB;
@@ -2,7 +2,6 @@ package modelos
 
 import (
 	"api/src/seguranca"
-	"errors"
 	"strings"
 	"time"
 
@@ -17,6 +16,29 @@ type Usuario struct {
 	Email string `json:"email,omitempty"`
 	Senha string `json:"senha,omitempty"`
 	CriadoEm time.Time `json:"criadoEm,omitempty"`
+	// Privado indica conta privada: publicações e seguidores visíveis somente para seguidores.
+	// É nulo quando a atualização não informa o campo, que mantém o valor gravado.
+	Privado *bool `json:"privado,omitempty"`
+	// Papel e SuspensoEm só são preenchidos nas consultas de autenticação e de administração
+	Papel string `json:"papel,omitempty"`
+	SuspensoEm *time.Time `json:"suspensoEm,omitempty"`
+	// VerificadoEm é nulo enquanto o usuário não confirma o email. Só é preenchido nas consultas de autenticação.
+	VerificadoEm *time.Time `json:"-"`
+}
+
+// Suspenso indica se a conta foi suspensa pela moderação
+func (usuario Usuario) Suspenso() bool {
+	return usuario.SuspensoEm != nil
+}
+
+// ContaPrivada indica se as publicações e os seguidores só são visíveis para seguidores
+func (usuario Usuario) ContaPrivada() bool {
+	return usuario.Privado != nil && *usuario.Privado
+}
+
+// Verificado indica se o usuário já confirmou o email
+func (usuario Usuario) Verificado() bool {
+	return usuario.VerificadoEm != nil
 }
 
 // Preparar irá validar e formatar dados do usuário
@@ -31,27 +53,31 @@ func (usuario *Usuario) Preparar(etapa string) error {
 }
 
 func (usuario *Usuario) validar(etapa string) error {
+	var erroDeValidacao ErroDeValidacao
 
 	if usuario.Nome == "" {
-		return errors.New("nome obrigatório")
+		erroDeValidacao.adicionar("nome", "nome obrigatório")
 	}
 
 	if usuario.Nick == "" {
-		return errors.New("nick obrigatório")
+		erroDeValidacao.adicionar("nick", "nick obrigatório")
 	}
 
 	if usuario.Email == "" {
-		return errors.New("email obrigatório")
-	}
-	if erro := checkmail.ValidateFormat(usuario.Email); erro != nil {
-		return errors.New("formato de email inválido")
+		erroDeValidacao.adicionar("email", "email obrigatório")
+	} else if erro := checkmail.ValidateFormat(usuario.Email); erro != nil {
+		erroDeValidacao.adicionar("email", "formato de email inválido")
 	}
 
-	if etapa == "cadastro" && usuario.Senha == "" {
-		return errors.New("senha obrigatório")
+	if etapa == "cadastro" {
+		if usuario.Senha == "" {
+			erroDeValidacao.adicionar("senha", "senha obrigatório")
+		} else {
+			validarPoliticaDeSenha(&erroDeValidacao, "senha", usuario.Senha, usuario.Nick, usuario.Email)
+		}
 	}
 
-	return nil
+	return erroDeValidacao.resultado()
 }
 
 func (usuario *Usuario) formatar(etapa string) error {
@@ -0,0 +1,166 @@
+package modelos
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// tamanhoMaximoDaBusca limita o texto aceito em uma busca, em caracteres
+	tamanhoMaximoDaBusca = 100
+
+	// tamanhoDoTrecho é a quantidade aproximada de caracteres do trecho devolvido em cada resultado
+	tamanhoDoTrecho = 160
+
+	// contextoDoTrecho é a quantidade de caracteres exibidos antes do primeiro termo encontrado
+	contextoDoTrecho = 40
+)
+
+// PublicacaoEncontrada é uma publicação retornada pela busca, com sua relevância e o trecho
+// do conteúdo onde os termos aparecem destacados com <mark>
+type PublicacaoEncontrada struct {
+	Publicacao
+	Relevancia float64 `json:"relevancia"`
+	Trecho     string  `json:"trecho"`
+}
+
+// PrepararBusca valida o texto da busca e retorna os termos que o compõem, em minúsculas e sem repetições
+func PrepararBusca(busca string) (string, []string, error) {
+	var erroDeValidacao ErroDeValidacao
+
+	busca = strings.TrimSpace(busca)
+	termos := TermosDaBusca(busca)
+
+	if len(termos) == 0 {
+		erroDeValidacao.adicionar("q", "informe o que deseja buscar")
+	}
+	if utf8.RuneCountInString(busca) > tamanhoMaximoDaBusca {
+		erroDeValidacao.adicionar("q", "a busca deve ter no máximo 100 caracteres")
+	}
+
+	if erro := erroDeValidacao.resultado(); erro != nil {
+		return "", nil, erro
+	}
+
+	return busca, termos, nil
+}
+
+// TermosDaBusca separa o texto em palavras, em minúsculas e sem repetições
+func TermosDaBusca(busca string) []string {
+	termos := make([]string, 0)
+	vistos := make(map[string]bool)
+
+	for _, palavra := range strings.FieldsFunc(strings.ToLower(busca), separadorDePalavras) {
+		if !vistos[palavra] {
+			vistos[palavra] = true
+			termos = append(termos, palavra)
+		}
+	}
+
+	return termos
+}
+
+// DestacarTrecho recorta do texto um trecho a partir do primeiro termo encontrado e envolve
+// cada ocorrência dos termos com <mark>. O restante do texto é escapado, pois é conteúdo do usuário.
+func DestacarTrecho(texto string, termos []string) string {
+	procurados := make(map[string]bool, len(termos))
+	for _, termo := range termos {
+		procurados[termo] = true
+	}
+
+	caracteres := []rune(texto)
+	palavras := localizarPalavras(caracteres)
+
+	inicio := 0
+	for _, palavra := range palavras {
+		if procurados[strings.ToLower(string(caracteres[palavra[0]:palavra[1]]))] {
+			inicio = palavra[0] - contextoDoTrecho
+			break
+		}
+	}
+	if inicio < 0 {
+		inicio = 0
+	}
+
+	fim := inicio + tamanhoDoTrecho
+	if fim > len(caracteres) {
+		fim = len(caracteres)
+	}
+
+	// Evita cortar palavras nas pontas do trecho, a menos que uma única palavra ocupe o trecho inteiro
+	ajustado := inicio
+	for ajustado > 0 && ajustado < fim && !separadorDePalavras(caracteres[ajustado-1]) {
+		ajustado++
+	}
+	if ajustado < fim {
+		inicio = ajustado
+	}
+
+	ajustado = fim
+	for ajustado < len(caracteres) && ajustado > inicio && !separadorDePalavras(caracteres[ajustado]) {
+		ajustado--
+	}
+	if ajustado > inicio {
+		fim = ajustado
+	}
+
+	var trecho strings.Builder
+	if inicio > 0 {
+		trecho.WriteString("…")
+	}
+
+	posicao := inicio
+	for _, palavra := range palavras {
+		if palavra[0] < inicio || palavra[1] > fim {
+			continue
+		}
+
+		trecho.WriteString(html.EscapeString(string(caracteres[posicao:palavra[0]])))
+
+		conteudo := html.EscapeString(string(caracteres[palavra[0]:palavra[1]]))
+		if procurados[strings.ToLower(string(caracteres[palavra[0]:palavra[1]]))] {
+			conteudo = "<mark>" + conteudo + "</mark>"
+		}
+		trecho.WriteString(conteudo)
+
+		posicao = palavra[1]
+	}
+	trecho.WriteString(html.EscapeString(string(caracteres[posicao:fim])))
+
+	if fim < len(caracteres) {
+		trecho.WriteString("…")
+	}
+
+	return strings.TrimSpace(trecho.String())
+}
+
+// localizarPalavras retorna o início e o fim (exclusivo) de cada palavra do texto
+func localizarPalavras(caracteres []rune) [][2]int {
+	palavras := make([][2]int, 0)
+
+	inicio := -1
+	for i, caractere := range caracteres {
+		if separadorDePalavras(caractere) {
+			if inicio >= 0 {
+				palavras = append(palavras, [2]int{inicio, i})
+				inicio = -1
+			}
+			continue
+		}
+
+		if inicio < 0 {
+			inicio = i
+		}
+	}
+	if inicio >= 0 {
+		palavras = append(palavras, [2]int{inicio, len(caracteres)})
+	}
+
+	return palavras
+}
+
+func separadorDePalavras(caractere rune) bool {
+	return !unicode.IsLetter(caractere) && !unicode.IsDigit(caractere)
+}
@@ -0,0 +1,43 @@
+package modelos
+
+import (
+	"strings"
+	"time"
+)
+
+// Comentario representa um comentário feito por um usuário em uma publicação
+type Comentario struct {
+	ID           uint64    `json:"id,omitempty"`
+	PublicacaoId uint64    `json:"publicacaoId,omitempty"`
+	AutorId      uint64    `json:"autorId,omitempty"`
+	AutorNick    string    `json:"autorNick,omitempty"`
+	Conteudo     string    `json:"conteudo,omitempty"`
+	CriadoEm     time.Time `json:"criadoEm,omitempty"`
+}
+
+// Preparar valida e formata dados do comentário
+func (comentario *Comentario) Preparar() error {
+	if erro := comentario.validar(); erro != nil {
+		return erro
+	}
+
+	comentario.formatar()
+
+	return nil
+}
+
+func (comentario *Comentario) validar() error {
+	var erroDeValidacao ErroDeValidacao
+
+	if strings.TrimSpace(comentario.Conteudo) == "" {
+		erroDeValidacao.adicionar("conteudo", "conteúdo não pode estar em branco")
+	} else if len([]rune(strings.TrimSpace(comentario.Conteudo))) > 500 {
+		erroDeValidacao.adicionar("conteudo", "conteúdo não pode ter mais de 500 caracteres")
+	}
+
+	return erroDeValidacao.resultado()
+}
+
+func (comentario *Comentario) formatar() {
+	comentario.Conteudo = strings.TrimSpace(comentario.Conteudo)
+}
@@ -0,0 +1,85 @@
+package modelos
+
+import (
+	"strings"
+	"time"
+)
+
+// Motivos aceitos em uma denúncia
+const (
+	MotivoSpam              = "spam"
+	MotivoAssedio           = "assedio"
+	MotivoDiscursoDeOdio    = "discurso_de_odio"
+	MotivoConteudoImproprio = "conteudo_improprio"
+	MotivoFalsaIdentidade   = "falsa_identidade"
+	MotivoOutro             = "outro"
+)
+
+// Situações de uma denúncia na fila de moderação
+const (
+	DenunciaPendente   = "pendente"
+	DenunciaResolvida  = "resolvida"
+	DenunciaDescartada = "descartada"
+)
+
+var motivosDeDenuncia = map[string]bool{
+	MotivoSpam:              true,
+	MotivoAssedio:           true,
+	MotivoDiscursoDeOdio:    true,
+	MotivoConteudoImproprio: true,
+	MotivoFalsaIdentidade:   true,
+	MotivoOutro:             true,
+}
+
+// Denuncia representa a denúncia de uma publicação ou de um usuário. Somente um entre PublicacaoId e UsuarioId é preenchido.
+type Denuncia struct {
+	ID            uint64     `json:"id,omitempty"`
+	DenuncianteId uint64     `json:"denuncianteId,omitempty"`
+	PublicacaoId  *uint64    `json:"publicacaoId,omitempty"`
+	UsuarioId     *uint64    `json:"usuarioId,omitempty"`
+	Motivo        string     `json:"motivo,omitempty"`
+	Descricao     string     `json:"descricao,omitempty"`
+	Situacao      string     `json:"situacao,omitempty"`
+	ModeradorId   *uint64    `json:"moderadorId,omitempty"`
+	ModeradaEm    *time.Time `json:"moderadaEm,omitempty"`
+	CriadaEm      time.Time  `json:"criadaEm,omitempty"`
+}
+
+// Preparar valida e formata dados da denúncia
+func (denuncia *Denuncia) Preparar() error {
+	if erro := denuncia.validar(); erro != nil {
+		return erro
+	}
+
+	denuncia.formatar()
+
+	return nil
+}
+
+func (denuncia *Denuncia) validar() error {
+	var erroDeValidacao ErroDeValidacao
+
+	if denuncia.Motivo == "" {
+		erroDeValidacao.adicionar("motivo", "motivo obrigatório")
+	} else if !motivosDeDenuncia[denuncia.Motivo] {
+		erroDeValidacao.adicionar("motivo", "motivo deve ser spam, assedio, discurso_de_odio, conteudo_improprio, falsa_identidade ou outro")
+	}
+
+	descricao := strings.TrimSpace(denuncia.Descricao)
+	if denuncia.Motivo == MotivoOutro && descricao == "" {
+		erroDeValidacao.adicionar("descricao", "descrição obrigatória para o motivo outro")
+	} else if len([]rune(descricao)) > 500 {
+		erroDeValidacao.adicionar("descricao", "descrição não pode ter mais de 500 caracteres")
+	}
+
+	return erroDeValidacao.resultado()
+}
+
+func (denuncia *Denuncia) formatar() {
+	denuncia.Descricao = strings.TrimSpace(denuncia.Descricao)
+}
+
+// SituacaoDeDenunciaValida indica se a situação informada existe, para filtrar a fila de moderação
+func SituacaoDeDenunciaValida(situacao string) bool {
+	return situacao == DenunciaPendente || situacao == DenunciaResolvida || situacao == DenunciaDescartada
+}
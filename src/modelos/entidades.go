@@ -0,0 +1,148 @@
+package modelos
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	// EntidadeHashtag marca um trecho "#tag" do conteúdo
+	EntidadeHashtag = "hashtag"
+
+	// EntidadeMencao marca um trecho "@nick" do conteúdo que corresponde a um usuário existente
+	EntidadeMencao = "mencao"
+
+	// tamanhoMaximoDaHashtag é o tamanho da coluna publicacoes_hashtags.hashtag
+	tamanhoMaximoDaHashtag = 100
+)
+
+// Entidade é um trecho do conteúdo de uma publicação que os clientes podem exibir como link.
+// Inicio e Fim (exclusivo) são posições em caracteres (code points) do conteúdo e incluem o "#" ou o "@".
+type Entidade struct {
+	Tipo      string `json:"tipo"`
+	Inicio    int    `json:"inicio"`
+	Fim       int    `json:"fim"`
+	Texto     string `json:"texto"`
+	UsuarioId uint64 `json:"usuarioId,omitempty"`
+}
+
+// Mencao é uma ocorrência de "@nick" no conteúdo de uma publicação.
+// UsuarioId só é conhecido depois que o nick é resolvido no banco.
+type Mencao struct {
+	Nick      string
+	UsuarioId uint64
+	Inicio    int
+	Fim       int
+}
+
+// HashtagEmAlta é uma hashtag com a quantidade de publicações que a usaram dentro da janela consultada
+type HashtagEmAlta struct {
+	Hashtag     string `json:"hashtag"`
+	Publicacoes uint64 `json:"publicacoes"`
+}
+
+// NormalizarHashtag remove o "#" inicial e coloca a hashtag em minúsculas, forma em que é armazenada
+func NormalizarHashtag(hashtag string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(hashtag), "#"))
+}
+
+// HashtagValida indica se a hashtag normalizada pode ser armazenada: precisa ter ao menos uma letra
+func HashtagValida(hashtag string) bool {
+	return strings.ContainsFunc(hashtag, unicode.IsLetter) && len(hashtag) <= tamanhoMaximoDaHashtag
+}
+
+// extrairEntidades preenche as hashtags (sem repetições) e as menções da publicação a partir do conteúdo
+func (publicacao *Publicacao) extrairEntidades() {
+	publicacao.Hashtags = make([]string, 0)
+	publicacao.Mencoes = make([]Mencao, 0)
+	vistas := make(map[string]bool)
+
+	for _, entidade := range localizarEntidades(publicacao.Conteudo) {
+		switch entidade.Tipo {
+		case EntidadeHashtag:
+			if !vistas[entidade.Texto] {
+				vistas[entidade.Texto] = true
+				publicacao.Hashtags = append(publicacao.Hashtags, entidade.Texto)
+			}
+		case EntidadeMencao:
+			publicacao.Mencoes = append(publicacao.Mencoes, Mencao{Nick: entidade.Texto, Inicio: entidade.Inicio, Fim: entidade.Fim})
+		}
+	}
+}
+
+// MontarEntidades preenche as entidades da publicação a partir do conteúdo. Somente as menções
+// resolvidas (as informadas, vindas do banco) viram entidades, com o id do usuário mencionado.
+func (publicacao *Publicacao) MontarEntidades(mencoes []Mencao) {
+	resolvidas := make(map[int]uint64, len(mencoes))
+	for _, mencao := range mencoes {
+		resolvidas[mencao.Inicio] = mencao.UsuarioId
+	}
+
+	publicacao.Entidades = make([]Entidade, 0)
+	for _, entidade := range localizarEntidades(publicacao.Conteudo) {
+		if entidade.Tipo == EntidadeMencao {
+			usuarioId, resolvida := resolvidas[entidade.Inicio]
+			if !resolvida {
+				continue
+			}
+			entidade.UsuarioId = usuarioId
+		}
+
+		publicacao.Entidades = append(publicacao.Entidades, entidade)
+	}
+}
+
+// localizarEntidades percorre o conteúdo e retorna cada "#hashtag" e "@nick" na ordem em que aparecem.
+// O texto das hashtags já vem normalizado; o das menções, como foi escrito.
+func localizarEntidades(conteudo string) []Entidade {
+	entidades := make([]Entidade, 0)
+
+	caracteres := []rune(conteudo)
+	for i := 0; i < len(caracteres); i++ {
+		marcador := caracteres[i]
+		if marcador != '#' && marcador != '@' {
+			continue
+		}
+
+		// "a#b" e "email@dominio" não são entidades
+		if i > 0 && caractereDeEntidade(caracteres[i-1], '#') {
+			continue
+		}
+
+		fim := i + 1
+		for fim < len(caracteres) && caractereDeEntidade(caracteres[fim], marcador) {
+			fim++
+		}
+		// Pontos no final pertencem à frase, não à entidade
+		for fim > i+1 && caracteres[fim-1] == '.' {
+			fim--
+		}
+
+		texto := string(caracteres[i+1 : fim])
+		if texto == "" {
+			continue
+		}
+
+		if marcador == '#' {
+			texto = NormalizarHashtag(texto)
+			if HashtagValida(texto) {
+				entidades = append(entidades, Entidade{Tipo: EntidadeHashtag, Inicio: i, Fim: fim, Texto: texto})
+			}
+		} else {
+			entidades = append(entidades, Entidade{Tipo: EntidadeMencao, Inicio: i, Fim: fim, Texto: texto})
+		}
+
+		i = fim - 1
+	}
+
+	return entidades
+}
+
+// caractereDeEntidade indica se o caractere pode compor a entidade iniciada pelo marcador.
+// Nicks aceitam ponto (joao.silva); hashtags, não.
+func caractereDeEntidade(caractere, marcador rune) bool {
+	if caractere == '.' {
+		return marcador == '@'
+	}
+	return unicode.IsLetter(caractere) || unicode.IsDigit(caractere) || caractere == '_'
+}
@@ -0,0 +1,139 @@
+package modelos
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// LimitePadrao é a quantidade de itens por página quando o cliente não informa o limite
+	LimitePadrao = 20
+
+	// LimiteMaximo é a maior quantidade de itens por página aceita pela API
+	LimiteMaximo = 100
+)
+
+// Cursor aponta para o último item entregue em uma página.
+// A ordenação das listagens é feita por (criadoEm, id), o que mantém a paginação estável
+// mesmo quando novos registros são inseridos entre uma página e outra.
+// Nas buscas textuais a ordenação é feita por (relevancia, criadoEm, id).
+type Cursor struct {
+	Relevancia float64
+	CriadoEm   time.Time
+	ID         uint64
+}
+
+// Paginacao representa os parâmetros de paginação de uma listagem
+type Paginacao struct {
+	Limite uint64
+	// Cursor nulo indica a primeira página
+	Cursor *Cursor
+}
+
+// NovaPaginacao valida e monta a paginação a partir dos valores recebidos na query string
+func NovaPaginacao(limite, cursor string) (Paginacao, error) {
+	paginacao := Paginacao{Limite: LimitePadrao}
+
+	if limite != "" {
+		valor, erro := strconv.ParseUint(limite, 10, 64)
+		if erro != nil || valor == 0 {
+			return Paginacao{}, ErroDeValidacao{Campos: []CampoInvalido{{Campo: "limite", Mensagem: "limite inválido"}}}
+		}
+		if valor > LimiteMaximo {
+			valor = LimiteMaximo
+		}
+		paginacao.Limite = valor
+	}
+
+	if cursor != "" {
+		cursorDecodificado, erro := decodificarCursor(cursor)
+		if erro != nil {
+			return Paginacao{}, ErroDeValidacao{Campos: []CampoInvalido{{Campo: "cursor", Mensagem: erro.Error()}}}
+		}
+		paginacao.Cursor = &cursorDecodificado
+	}
+
+	return paginacao, nil
+}
+
+// Codificar transforma o cursor em uma string opaca para o cliente.
+// Um cursor nulo (não há próxima página) resulta em string vazia.
+func (cursor *Cursor) Codificar() string {
+	if cursor == nil {
+		return ""
+	}
+
+	valor := cursor.CriadoEm.UTC().Format(time.RFC3339Nano) + "|" + strconv.FormatUint(cursor.ID, 10)
+	if cursor.Relevancia != 0 {
+		valor += "|" + strconv.FormatFloat(cursor.Relevancia, 'g', -1, 64)
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(valor))
+}
+
+func decodificarCursor(cursor string) (Cursor, error) {
+	erroCursor := errors.New("cursor inválido")
+
+	valor, erro := base64.RawURLEncoding.DecodeString(cursor)
+	if erro != nil {
+		return Cursor{}, erroCursor
+	}
+
+	partes := strings.SplitN(string(valor), "|", 3)
+	if len(partes) < 2 {
+		return Cursor{}, erroCursor
+	}
+
+	criadoEm, erro := time.Parse(time.RFC3339Nano, partes[0])
+	if erro != nil {
+		return Cursor{}, erroCursor
+	}
+
+	id, erro := strconv.ParseUint(partes[1], 10, 64)
+	if erro != nil {
+		return Cursor{}, erroCursor
+	}
+
+	var relevancia float64
+	if len(partes) == 3 {
+		relevancia, erro = strconv.ParseFloat(partes[2], 64)
+		if erro != nil {
+			return Cursor{}, erroCursor
+		}
+	}
+
+	return Cursor{Relevancia: relevancia, CriadoEm: criadoEm, ID: id}, nil
+}
+
+// Posicao devolve data e id do cursor para uso como parâmetros de consulta.
+// Na primeira página ambos são nulos, permitindo filtros do tipo `? is null or (criadoEm, id) < (?, ?)`.
+func (paginacao Paginacao) Posicao() (interface{}, interface{}) {
+	if paginacao.Cursor == nil {
+		return nil, nil
+	}
+
+	return paginacao.Cursor.CriadoEm, paginacao.Cursor.ID
+}
+
+// PosicaoPorRelevancia devolve relevância, data e id do cursor para as buscas textuais.
+// Assim como em Posicao, os valores são nulos na primeira página.
+func (paginacao Paginacao) PosicaoPorRelevancia() (interface{}, interface{}, interface{}) {
+	if paginacao.Cursor == nil {
+		return nil, nil, nil
+	}
+
+	return paginacao.Cursor.Relevancia, paginacao.Cursor.CriadoEm, paginacao.Cursor.ID
+}
+
+// LimiteDaConsulta é o limite a ser usado na consulta ao banco: um item a mais que o pedido,
+// usado somente para saber se existe próxima página.
+func (paginacao Paginacao) LimiteDaConsulta() uint64 {
+	return paginacao.Limite + 1
+}
+
+// TemProximaPagina indica, a partir da quantidade de itens retornada pelo banco, se há próxima página
+func (paginacao Paginacao) TemProximaPagina(quantidade int) bool {
+	return uint64(quantidade) > paginacao.Limite
+}
@@ -0,0 +1,8 @@
+package modelos
+
+// Papéis de acesso dos usuários. Todo usuário é criado com PapelUsuario;
+// o papel de administrador é atribuído diretamente no banco.
+const (
+	PapelUsuario       = "usuario"
+	PapelAdministrador = "admin"
+)
@@ -1,7 +1,6 @@
 package modelos
 
 import (
-	"errors"
 	"strings"
 	"time"
 )
@@ -14,28 +13,44 @@ type Publicacao struct {
 	AuthorId uint64 `json:"autorId,omitempty"`
 	AuthorNick string `json:"authorNick,omitempty"`
 	Curtidas uint64 `json:"curtidas"`
+	CurtidoPorMim bool `json:"curtidoPorMim"`
+	TotalComentarios uint64 `json:"totalComentarios"`
 	CriadaEm time.Time `json:"criadaEm,omitempty"`
+	Entidades []Entidade `json:"entidades,omitempty"`
+	// Hashtags e Mencoes são extraídas do conteúdo por Preparar e gravadas junto com a publicação
+	Hashtags []string `json:"-"`
+	Mencoes []Mencao `json:"-"`
+	// OcultaEm só é preenchido por BuscarPorId; publicações ocultadas pela moderação só são exibidas ao autor e aos administradores
+	OcultaEm *time.Time `json:"-"`
 }
 
-//Preparar valida e formata dados da publicação
+// Oculta indica se a publicação foi ocultada por denúncias
+func (publicacao Publicacao) Oculta() bool {
+	return publicacao.OcultaEm != nil
+}
+
+//Preparar valida e formata dados da publicação e extrai as hashtags e menções do conteúdo
 func (publicacao *Publicacao) Preparar() error {
 	if erro := publicacao.validar(); erro != nil {
 		return erro
 	}
 
 	publicacao.formatar()
+	publicacao.extrairEntidades()
 
 	return nil
 }
 
 func (publicacao *Publicacao) validar() error {
+	var erroDeValidacao ErroDeValidacao
+
 	if publicacao.Titulo == "" {
-		return errors.New("título não pode estar em branco")
+		erroDeValidacao.adicionar("titulo", "título não pode estar em branco")
 	}
 	if publicacao.Conteudo == "" {
-		return errors.New("conteúdo não pode estar em branco")
+		erroDeValidacao.adicionar("conteudo", "conteúdo não pode estar em branco")
 	}
-	return nil
+	return erroDeValidacao.resultado()
 }
 
 func (publicacao *Publicacao) formatar() {
@@ -0,0 +1,18 @@
+package modelos
+
+import "time"
+
+// TokenDeRedefinicao representa um token de redefinição de senha persistido. Assim como no refresh token,
+// o valor enviado por email nunca é guardado, somente seu hash.
+type TokenDeRedefinicao struct {
+	ID        uint64
+	UsuarioId uint64
+	TokenHash string
+	ExpiraEm  time.Time
+	UsadoEm   *time.Time
+}
+
+// Valido indica se o token ainda pode ser utilizado
+func (token TokenDeRedefinicao) Valido() bool {
+	return token.ID != 0 && token.UsadoEm == nil && time.Now().Before(token.ExpiraEm)
+}
@@ -0,0 +1,19 @@
+package modelos
+
+import "time"
+
+// RefreshToken representa um refresh token persistido. O valor entregue ao cliente nunca é guardado, somente seu hash.
+type RefreshToken struct {
+	ID        uint64
+	UsuarioId uint64
+	TokenHash string
+	// AcessoJti identifica o token de acesso emitido junto com este refresh token
+	AcessoJti  string
+	ExpiraEm   time.Time
+	RevogadoEm *time.Time
+}
+
+// Valido indica se o refresh token ainda pode ser utilizado
+func (refreshToken RefreshToken) Valido() bool {
+	return refreshToken.ID != 0 && refreshToken.RevogadoEm == nil && time.Now().Before(refreshToken.ExpiraEm)
+}
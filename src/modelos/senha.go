@@ -1,7 +1,87 @@
 package modelos
 
+import (
+	"api/src/config"
+	"api/src/seguranca"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TamanhoMaximoSenha é o limite do bcrypt, em bytes: o que passa disso seria ignorado no hash
+const TamanhoMaximoSenha = 72
+
 // Senha representa o formato da atualização de senha
 type Senha struct {
-	Nova string `json:"nova"`
+	Nova  string `json:"nova"`
 	Atual string `json:"atual"`
-}
\ No newline at end of file
+}
+
+// Validar aplica a política de senha à nova senha do usuário de nick e email informados
+func (senha Senha) Validar(nick, email string) error {
+	var erroDeValidacao ErroDeValidacao
+
+	if senha.Nova == "" {
+		erroDeValidacao.adicionar("nova", "a nova senha é obrigatória")
+	} else {
+		validarPoliticaDeSenha(&erroDeValidacao, "nova", senha.Nova, nick, email)
+	}
+
+	return erroDeValidacao.resultado()
+}
+
+// validarPoliticaDeSenha adiciona ao erro, no campo informado, cada regra da política que a senha descumpre:
+// tamanho mínimo e máximo, tipos de caractere, igualdade com nick ou email e presença na lista de senhas comuns
+func validarPoliticaDeSenha(erroDeValidacao *ErroDeValidacao, campo, senha, nick, email string) {
+	if utf8.RuneCountInString(senha) < config.SenhaTamanhoMinimo {
+		erroDeValidacao.adicionar(campo, fmt.Sprintf("a senha deve ter pelo menos %d caracteres", config.SenhaTamanhoMinimo))
+	}
+
+	if len(senha) > TamanhoMaximoSenha {
+		erroDeValidacao.adicionar(campo, fmt.Sprintf("a senha deve ter no máximo %d bytes", TamanhoMaximoSenha))
+	}
+
+	if classesDeCaractere(senha) < config.SenhaClassesMinimas {
+		erroDeValidacao.adicionar(campo, fmt.Sprintf(
+			"a senha deve combinar pelo menos %d destes tipos de caractere: letras minúsculas, letras maiúsculas, números e símbolos",
+			config.SenhaClassesMinimas,
+		))
+	}
+
+	nick = strings.TrimSpace(nick)
+	email = strings.TrimSpace(email)
+	if (nick != "" && strings.EqualFold(senha, nick)) || (email != "" && strings.EqualFold(senha, email)) {
+		erroDeValidacao.adicionar(campo, "a senha não pode ser igual ao nick ou ao email")
+	}
+
+	if seguranca.SenhaComum(senha) {
+		erroDeValidacao.adicionar(campo, "a senha está entre as mais comuns, escolha outra")
+	}
+}
+
+// classesDeCaractere conta quantos tipos de caractere diferentes a senha combina
+func classesDeCaractere(senha string) int {
+	var minuscula, maiuscula, numero, simbolo bool
+	for _, caractere := range senha {
+		switch {
+		case unicode.IsLower(caractere):
+			minuscula = true
+		case unicode.IsUpper(caractere):
+			maiuscula = true
+		case unicode.IsDigit(caractere):
+			numero = true
+		default:
+			simbolo = true
+		}
+	}
+
+	classes := 0
+	for _, presente := range []bool{minuscula, maiuscula, numero, simbolo} {
+		if presente {
+			classes++
+		}
+	}
+
+	return classes
+}
@@ -0,0 +1,38 @@
+package modelos
+
+import "strings"
+
+// CampoInvalido descreve um campo que não passou na validação
+type CampoInvalido struct {
+	Campo    string `json:"campo"`
+	Mensagem string `json:"mensagem"`
+}
+
+// ErroDeValidacao é retornado pelos métodos Preparar quando os dados recebidos são inválidos,
+// indicando cada campo inválido e o motivo
+type ErroDeValidacao struct {
+	Campos []CampoInvalido
+}
+
+func (erro ErroDeValidacao) Error() string {
+	mensagens := make([]string, 0, len(erro.Campos))
+	for _, campo := range erro.Campos {
+		mensagens = append(mensagens, campo.Mensagem)
+	}
+
+	return strings.Join(mensagens, "; ")
+}
+
+// adicionar inclui um campo inválido no erro
+func (erro *ErroDeValidacao) adicionar(campo, mensagem string) {
+	erro.Campos = append(erro.Campos, CampoInvalido{Campo: campo, Mensagem: mensagem})
+}
+
+// resultado retorna o erro somente quando há campos inválidos
+func (erro ErroDeValidacao) resultado() error {
+	if len(erro.Campos) == 0 {
+		return nil
+	}
+
+	return erro
+}
@@ -0,0 +1,182 @@
+package repositorios
+
+import (
+	"api/src/modelos"
+	"time"
+)
+
+// Bloquear registra o bloqueio e desfaz, na mesma transação, os vínculos de seguidor e os pedidos para seguir
+// nos dois sentidos
+func (repositorio Usuarios) Bloquear(usuarioId, bloqueadoId uint64) error {
+	transacao, erro := repositorio.db.Begin()
+	if erro != nil {
+		return erro
+	}
+	defer transacao.Rollback()
+
+	if _, erro = transacao.Exec(
+		"insert ignore into bloqueios (usuario_id, bloqueado_id) values (?, ?)",
+		usuarioId, bloqueadoId,
+	); erro != nil {
+		return erro
+	}
+
+	if _, erro = transacao.Exec(
+		`delete from seguidores
+		where (usuario_id = ? and seguidor_id = ?) or (usuario_id = ? and seguidor_id = ?)`,
+		usuarioId, bloqueadoId, bloqueadoId, usuarioId,
+	); erro != nil {
+		return erro
+	}
+
+	if _, erro = transacao.Exec(
+		`delete from solicitacoes_seguir
+		where (usuario_id = ? and solicitante_id = ?) or (usuario_id = ? and solicitante_id = ?)`,
+		usuarioId, bloqueadoId, bloqueadoId, usuarioId,
+	); erro != nil {
+		return erro
+	}
+
+	return transacao.Commit()
+}
+
+// Desbloquear remove o bloqueio feito pelo usuário. Os vínculos de seguidor desfeitos não são restaurados.
+func (repositorio Usuarios) Desbloquear(usuarioId, bloqueadoId uint64) error {
+	statement, erro := repositorio.db.Prepare(
+		"delete from bloqueios where usuario_id = ? and bloqueado_id = ?",
+	)
+	if erro != nil {
+		return erro
+	}
+	defer statement.Close()
+
+	if _, erro := statement.Exec(usuarioId, bloqueadoId); erro != nil {
+		return erro
+	}
+
+	return nil
+}
+
+// Bloqueado indica se existe bloqueio entre os dois usuários, em qualquer sentido
+func (repositorio Usuarios) Bloqueado(usuarioId, outroUsuarioId uint64) (bool, error) {
+	var bloqueado bool
+
+	if erro := repositorio.db.QueryRow(
+		`select exists(
+			select 1 from bloqueios
+			where (usuario_id = ? and bloqueado_id = ?) or (usuario_id = ? and bloqueado_id = ?)
+		)`,
+		usuarioId, outroUsuarioId, outroUsuarioId, usuarioId,
+	).Scan(&bloqueado); erro != nil {
+		return false, erro
+	}
+
+	return bloqueado, nil
+}
+
+// BuscarBloqueados retorna os usuários bloqueados pelo usuário, dos bloqueios mais recentes para os mais antigos
+func (repositorio Usuarios) BuscarBloqueados(usuarioId uint64, paginacao modelos.Paginacao) ([]modelos.Usuario, *modelos.Cursor, error) {
+	return repositorio.buscarRestritos(
+		`SELECT u.id, u.nome, u.nick, u.email, b.criadoEm from bloqueios b
+		join usuarios u on u.id = b.bloqueado_id
+		WHERE b.usuario_id = ?
+		and (? is null or (b.criadoEm, u.id) < (?, ?))
+		order by b.criadoEm desc, u.id desc
+		limit ?`,
+		usuarioId, paginacao,
+	)
+}
+
+// Silenciar esconde as publicações do silenciado do feed do usuário
+func (repositorio Usuarios) Silenciar(usuarioId, silenciadoId uint64) error {
+	statement, erro := repositorio.db.Prepare(
+		"insert ignore into silenciados (usuario_id, silenciado_id) values (?, ?)",
+	)
+	if erro != nil {
+		return erro
+	}
+	defer statement.Close()
+
+	if _, erro := statement.Exec(usuarioId, silenciadoId); erro != nil {
+		return erro
+	}
+
+	return nil
+}
+
+// Dessilenciar volta a exibir as publicações do silenciado no feed do usuário
+func (repositorio Usuarios) Dessilenciar(usuarioId, silenciadoId uint64) error {
+	statement, erro := repositorio.db.Prepare(
+		"delete from silenciados where usuario_id = ? and silenciado_id = ?",
+	)
+	if erro != nil {
+		return erro
+	}
+	defer statement.Close()
+
+	if _, erro := statement.Exec(usuarioId, silenciadoId); erro != nil {
+		return erro
+	}
+
+	return nil
+}
+
+// BuscarSilenciados retorna os usuários silenciados pelo usuário, dos mais recentes para os mais antigos
+func (repositorio Usuarios) BuscarSilenciados(usuarioId uint64, paginacao modelos.Paginacao) ([]modelos.Usuario, *modelos.Cursor, error) {
+	return repositorio.buscarRestritos(
+		`SELECT u.id, u.nome, u.nick, u.email, s.criadoEm from silenciados s
+		join usuarios u on u.id = s.silenciado_id
+		WHERE s.usuario_id = ?
+		and (? is null or (s.criadoEm, u.id) < (?, ?))
+		order by s.criadoEm desc, u.id desc
+		limit ?`,
+		usuarioId, paginacao,
+	)
+}
+
+// buscarRestritos executa a listagem de bloqueados, silenciados ou pedidos para seguir, paginada pela data
+// do registro e pelo id do usuário
+func (repositorio Usuarios) buscarRestritos(consulta string, usuarioId uint64, paginacao modelos.Paginacao) ([]modelos.Usuario, *modelos.Cursor, error) {
+	cursorData, cursorId := paginacao.Posicao()
+
+	linhas, erro := repositorio.db.Query(
+		consulta,
+		usuarioId,
+		cursorData, cursorData, cursorId,
+		paginacao.LimiteDaConsulta(),
+	)
+	if erro != nil {
+		return nil, nil, erro
+	}
+	defer linhas.Close()
+
+	usuarios := make([]modelos.Usuario, 0)
+	datasDaRestricao := make([]time.Time, 0)
+
+	for linhas.Next() {
+		var usuario modelos.Usuario
+		var restritoDesde time.Time
+
+		if erro = linhas.Scan(
+			&usuario.ID,
+			&usuario.Nome,
+			&usuario.Nick,
+			&usuario.Email,
+			&restritoDesde,
+		); erro != nil {
+			return nil, nil, erro
+		}
+
+		usuarios = append(usuarios, usuario)
+		datasDaRestricao = append(datasDaRestricao, restritoDesde)
+	}
+
+	var proximoCursor *modelos.Cursor
+	if paginacao.TemProximaPagina(len(usuarios)) {
+		usuarios = usuarios[:paginacao.Limite]
+		ultimo := len(usuarios) - 1
+		proximoCursor = &modelos.Cursor{CriadoEm: datasDaRestricao[ultimo], ID: usuarios[ultimo].ID}
+	}
+
+	return usuarios, proximoCursor, nil
+}
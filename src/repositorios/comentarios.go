@@ -0,0 +1,151 @@
+package repositorios
+
+import (
+	"api/src/modelos"
+	"database/sql"
+)
+
+// Comentarios representa o repositório de comentários
+type Comentarios struct {
+	db *sql.DB
+}
+
+// NovoRepositorioDeComentarios cria um repositório de comentários
+func NovoRepositorioDeComentarios(db *sql.DB) *Comentarios {
+	return &Comentarios{db}
+}
+
+// Criar insere um comentário no banco de dados
+func (repositorio Comentarios) Criar(comentario modelos.Comentario) (uint64, error) {
+	statement, erro := repositorio.db.Prepare(
+		`insert into comentarios (publicacao_id, autor_id, conteudo)
+		 values (?, ?, ?)`)
+	if erro != nil {
+		return 0, erro
+	}
+	defer statement.Close()
+
+	resultado, erro := statement.Exec(comentario.PublicacaoId, comentario.AutorId, comentario.Conteudo)
+	if erro != nil {
+		return 0, erro
+	}
+
+	ultimoIdInserido, erro := resultado.LastInsertId()
+	if erro != nil {
+		return 0, erro
+	}
+
+	return uint64(ultimoIdInserido), nil
+}
+
+// BuscarPorPublicacao retorna os comentários de uma publicação, do mais antigo para o mais recente,
+// paginados por (criadoEm, id)
+func (repositorio Comentarios) BuscarPorPublicacao(publicacaoId uint64, paginacao modelos.Paginacao) ([]modelos.Comentario, *modelos.Cursor, error) {
+	cursorData, cursorId := paginacao.Posicao()
+
+	linhas, erro := repositorio.db.Query(
+		`select c.id, c.publicacao_id, c.autor_id, u.nick, c.conteudo, c.criadoEm
+		from comentarios c
+		inner join usuarios u on u.id = c.autor_id
+		where c.publicacao_id = ?
+		and (? is null or (c.criadoEm, c.id) > (?, ?))
+		order by c.criadoEm, c.id
+		limit ?`,
+		publicacaoId,
+		cursorData, cursorData, cursorId,
+		paginacao.LimiteDaConsulta(),
+	)
+	if erro != nil {
+		return nil, nil, erro
+	}
+	defer linhas.Close()
+
+	comentarios := make([]modelos.Comentario, 0)
+
+	for linhas.Next() {
+		var comentario modelos.Comentario
+
+		if erro = linhas.Scan(
+			&comentario.ID,
+			&comentario.PublicacaoId,
+			&comentario.AutorId,
+			&comentario.AutorNick,
+			&comentario.Conteudo,
+			&comentario.CriadoEm,
+		); erro != nil {
+			return nil, nil, erro
+		}
+
+		comentarios = append(comentarios, comentario)
+	}
+
+	var proximoCursor *modelos.Cursor
+	if paginacao.TemProximaPagina(len(comentarios)) {
+		comentarios = comentarios[:paginacao.Limite]
+		ultimo := comentarios[len(comentarios)-1]
+		proximoCursor = &modelos.Cursor{CriadoEm: ultimo.CriadoEm, ID: ultimo.ID}
+	}
+
+	return comentarios, proximoCursor, nil
+}
+
+// BuscarPorId retorna dados de um comentário dado seu ID
+func (repositorio Comentarios) BuscarPorId(comentarioId uint64) (modelos.Comentario, error) {
+	linhas, erro := repositorio.db.Query(
+		`select c.id, c.publicacao_id, c.autor_id, u.nick, c.conteudo, c.criadoEm
+		from comentarios c
+		inner join usuarios u on u.id = c.autor_id
+		where c.id = ?`, comentarioId,
+	)
+	if erro != nil {
+		return modelos.Comentario{}, erro
+	}
+	defer linhas.Close()
+
+	var comentario modelos.Comentario
+
+	if linhas.Next() {
+		if erro = linhas.Scan(
+			&comentario.ID,
+			&comentario.PublicacaoId,
+			&comentario.AutorId,
+			&comentario.AutorNick,
+			&comentario.Conteudo,
+			&comentario.CriadoEm,
+		); erro != nil {
+			return modelos.Comentario{}, erro
+		}
+	}
+
+	return comentario, nil
+}
+
+// Atualizar altera o conteúdo de um comentário
+func (repositorio Comentarios) Atualizar(comentarioId uint64, comentario modelos.Comentario) error {
+	statement, erro := repositorio.db.Prepare(`update comentarios set conteudo=? where id=?`)
+	if erro != nil {
+		return erro
+	}
+	defer statement.Close()
+
+	if _, erro := statement.Exec(comentario.Conteudo, comentarioId); erro != nil {
+		return erro
+	}
+
+	return nil
+}
+
+// Remover remove um comentário da tabela
+func (repositorio Comentarios) Remover(comentarioId uint64) error {
+	statement, erro := repositorio.db.Prepare(`delete from comentarios where id=?`)
+	if erro != nil {
+		return erro
+	}
+	defer statement.Close()
+
+	if _, erro := statement.Exec(comentarioId); erro != nil {
+		return erro
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+package repositorios
+
+import (
+	"api/src/modelos"
+	"database/sql"
+	"errors"
+)
+
+// Denuncias representa o repositório de denúncias e da fila de moderação
+type Denuncias struct {
+	db *sql.DB
+}
+
+// NovoRepositorioDeDenuncias cria um repositório de denúncias
+func NovoRepositorioDeDenuncias(db *sql.DB) *Denuncias {
+	return &Denuncias{db}
+}
+
+// Criar insere a denúncia. Denunciar novamente a mesma publicação ou usuário resulta em ErroDeDuplicidade.
+func (repositorio Denuncias) Criar(denuncia modelos.Denuncia) (uint64, error) {
+	statement, erro := repositorio.db.Prepare(
+		`insert into denuncias (denunciante_id, publicacao_id, usuario_id, motivo, descricao)
+		values (?, ?, ?, ?, ?)`,
+	)
+	if erro != nil {
+		return 0, erro
+	}
+	defer statement.Close()
+
+	resultado, erro := statement.Exec(denuncia.DenuncianteId, denuncia.PublicacaoId, denuncia.UsuarioId, denuncia.Motivo, denuncia.Descricao)
+	if erro != nil {
+		return 0, traduzirErro(erro)
+	}
+
+	ultimoIdInserido, erro := resultado.LastInsertId()
+	if erro != nil {
+		return 0, erro
+	}
+
+	return uint64(ultimoIdInserido), nil
+}
+
+// ContarDenunciantes retorna quantos usuários distintos denunciaram a publicação, desconsiderando denúncias descartadas
+func (repositorio Denuncias) ContarDenunciantes(publicacaoId uint64) (uint64, error) {
+	var total uint64
+
+	if erro := repositorio.db.QueryRow(
+		`select count(distinct denunciante_id) from denuncias
+		where publicacao_id = ? and situacao <> ?`,
+		publicacaoId, modelos.DenunciaDescartada,
+	).Scan(&total); erro != nil {
+		return 0, erro
+	}
+
+	return total, nil
+}
+
+// BuscarPorSituacao retorna a fila de moderação, das denúncias mais antigas para as mais recentes,
+// paginada por (criadaEm, id)
+func (repositorio Denuncias) BuscarPorSituacao(situacao string, paginacao modelos.Paginacao) ([]modelos.Denuncia, *modelos.Cursor, error) {
+	cursorData, cursorId := paginacao.Posicao()
+
+	linhas, erro := repositorio.db.Query(
+		`select id, denunciante_id, publicacao_id, usuario_id, motivo, descricao, situacao, moderador_id, moderadaEm, criadaEm
+		from denuncias
+		where situacao = ?
+		and (? is null or (criadaEm, id) > (?, ?))
+		order by criadaEm, id
+		limit ?`,
+		situacao,
+		cursorData, cursorData, cursorId,
+		paginacao.LimiteDaConsulta(),
+	)
+	if erro != nil {
+		return nil, nil, erro
+	}
+	defer linhas.Close()
+
+	denuncias := make([]modelos.Denuncia, 0)
+
+	for linhas.Next() {
+		denuncia, erro := escanearDenuncia(linhas)
+		if erro != nil {
+			return nil, nil, erro
+		}
+
+		denuncias = append(denuncias, denuncia)
+	}
+
+	var proximoCursor *modelos.Cursor
+	if paginacao.TemProximaPagina(len(denuncias)) {
+		denuncias = denuncias[:paginacao.Limite]
+		ultima := denuncias[len(denuncias)-1]
+		proximoCursor = &modelos.Cursor{CriadoEm: ultima.CriadaEm, ID: ultima.ID}
+	}
+
+	return denuncias, proximoCursor, nil
+}
+
+// BuscarPorId retorna a denúncia. Denúncia inexistente resulta em ID zero.
+func (repositorio Denuncias) BuscarPorId(denunciaId uint64) (modelos.Denuncia, error) {
+	linha := repositorio.db.QueryRow(
+		`select id, denunciante_id, publicacao_id, usuario_id, motivo, descricao, situacao, moderador_id, moderadaEm, criadaEm
+		from denuncias where id = ?`,
+		denunciaId,
+	)
+
+	denuncia, erro := escanearDenuncia(linha)
+	if errors.Is(erro, sql.ErrNoRows) {
+		return modelos.Denuncia{}, nil
+	}
+
+	return denuncia, erro
+}
+
+// Moderar registra a decisão do moderador sobre uma denúncia pendente.
+// Retorna false se a denúncia já havia sido moderada.
+func (repositorio Denuncias) Moderar(denunciaId, moderadorId uint64, situacao string) (bool, error) {
+	statement, erro := repositorio.db.Prepare(
+		`update denuncias set situacao = ?, moderador_id = ?, moderadaEm = current_timestamp
+		where id = ? and situacao = ?`,
+	)
+	if erro != nil {
+		return false, erro
+	}
+	defer statement.Close()
+
+	resultado, erro := statement.Exec(situacao, moderadorId, denunciaId, modelos.DenunciaPendente)
+	if erro != nil {
+		return false, erro
+	}
+
+	linhasAfetadas, erro := resultado.RowsAffected()
+	if erro != nil {
+		return false, erro
+	}
+
+	return linhasAfetadas > 0, nil
+}
+
+// escaneavel é satisfeito por *sql.Row e *sql.Rows
+type escaneavel interface {
+	Scan(destino ...interface{}) error
+}
+
+func escanearDenuncia(linha escaneavel) (modelos.Denuncia, error) {
+	var denuncia modelos.Denuncia
+
+	if erro := linha.Scan(
+		&denuncia.ID,
+		&denuncia.DenuncianteId,
+		&denuncia.PublicacaoId,
+		&denuncia.UsuarioId,
+		&denuncia.Motivo,
+		&denuncia.Descricao,
+		&denuncia.Situacao,
+		&denuncia.ModeradorId,
+		&denuncia.ModeradaEm,
+		&denuncia.CriadaEm,
+	); erro != nil {
+		return modelos.Denuncia{}, erro
+	}
+
+	return denuncia, nil
+}
@@ -0,0 +1,37 @@
+package repositorios
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// codigoEntradaDuplicada é o código de erro do MySQL para violação de chave única (ER_DUP_ENTRY)
+const codigoEntradaDuplicada = 1062
+
+// ErroDeDuplicidade indica que o valor informado para um campo único já está em uso
+type ErroDeDuplicidade struct {
+	Campo string
+}
+
+func (erro ErroDeDuplicidade) Error() string {
+	return fmt.Sprintf("%s já está em uso", erro.Campo)
+}
+
+// traduzirErro converte erros do driver do MySQL em erros de domínio.
+// Erros sem tradução são devolvidos sem alteração.
+func traduzirErro(erro error) error {
+	var erroMySQL *mysql.MySQLError
+	if !errors.As(erro, &erroMySQL) || erroMySQL.Number != codigoEntradaDuplicada {
+		return erro
+	}
+
+	// Mensagem no formato: Duplicate entry 'valor' for key 'usuarios.nick'
+	indice := erroMySQL.Message[strings.LastIndex(erroMySQL.Message, " ")+1:]
+	indice = strings.Trim(indice, "'")
+	indice = indice[strings.LastIndex(indice, ".")+1:]
+
+	return ErroDeDuplicidade{Campo: indice}
+}
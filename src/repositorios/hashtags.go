@@ -0,0 +1,203 @@
+package repositorios
+
+import (
+	"api/src/modelos"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// gravarEntidades substitui as hashtags e menções da publicação pelas extraídas do conteúdo.
+// Menções de nicks inexistentes são descartadas.
+func gravarEntidades(transacao *sql.Tx, publicacaoId uint64, publicacao modelos.Publicacao) error {
+	if _, erro := transacao.Exec("delete from publicacoes_hashtags where publicacao_id = ?", publicacaoId); erro != nil {
+		return erro
+	}
+
+	if _, erro := transacao.Exec("delete from mencoes where publicacao_id = ?", publicacaoId); erro != nil {
+		return erro
+	}
+
+	for _, hashtag := range publicacao.Hashtags {
+		if _, erro := transacao.Exec(
+			`insert into publicacoes_hashtags (publicacao_id, hashtag, criadaEm)
+			select id, ?, criadaEm from publicacoes where id = ?`,
+			hashtag, publicacaoId,
+		); erro != nil {
+			return erro
+		}
+	}
+
+	for _, mencao := range publicacao.Mencoes {
+		if _, erro := transacao.Exec(
+			`insert into mencoes (publicacao_id, usuario_id, inicio, fim)
+			select ?, id, ?, ? from usuarios where nick = ?`,
+			publicacaoId, mencao.Inicio, mencao.Fim, mencao.Nick,
+		); erro != nil {
+			return erro
+		}
+	}
+
+	return nil
+}
+
+// BuscarPorHashtag retorna as publicações com a hashtag visíveis ao usuário logado, paginadas por (criadaEm, id).
+// Seguem as mesmas regras da busca: sem publicações ocultadas, de usuários com bloqueio ou de contas privadas não seguidas.
+func (repositorio Publicacoes) BuscarPorHashtag(hashtag string, usuarioLogadoId uint64, paginacao modelos.Paginacao) ([]modelos.Publicacao, *modelos.Cursor, error) {
+	cursorData, cursorId := paginacao.Posicao()
+
+	linhas, erro := repositorio.db.Query(
+		`select p.id, p.titulo, p.conteudo, p.autor_id, p.curtidas, p.criadaEm, u.nick,
+		exists(select 1 from curtidas c where c.publicacao_id = p.id and c.usuario_id = ?) as curtido_por_mim,
+		(select count(*) from comentarios co where co.publicacao_id = p.id) as total_comentarios
+		from publicacoes_hashtags h
+		inner join publicacoes p on p.id = h.publicacao_id
+		inner join usuarios u on u.id = p.autor_id
+		where h.hashtag = ?
+		and p.ocultaEm is null
+		and not exists(
+			select 1 from bloqueios b
+			where (b.usuario_id = ? and b.bloqueado_id = p.autor_id)
+			or (b.usuario_id = p.autor_id and b.bloqueado_id = ?)
+		)
+		and (u.privado = false or p.autor_id = ?
+			or exists(select 1 from seguidores s where s.usuario_id = p.autor_id and s.seguidor_id = ?))
+		and (? is null or (p.criadaEm, p.id) < (?, ?))
+		order by p.criadaEm desc, p.id desc
+		limit ?`,
+		usuarioLogadoId, hashtag,
+		usuarioLogadoId, usuarioLogadoId,
+		usuarioLogadoId, usuarioLogadoId,
+		cursorData, cursorData, cursorId,
+		paginacao.LimiteDaConsulta(),
+	)
+	if erro != nil {
+		return nil, nil, erro
+	}
+	defer linhas.Close()
+
+	publicacoes := make([]modelos.Publicacao, 0)
+
+	for linhas.Next() {
+		var publicacao modelos.Publicacao
+
+		if erro = linhas.Scan(
+			&publicacao.ID,
+			&publicacao.Titulo,
+			&publicacao.Conteudo,
+			&publicacao.AuthorId,
+			&publicacao.Curtidas,
+			&publicacao.CriadaEm,
+			&publicacao.AuthorNick,
+			&publicacao.CurtidoPorMim,
+			&publicacao.TotalComentarios,
+		); erro != nil {
+			return nil, nil, erro
+		}
+
+		publicacoes = append(publicacoes, publicacao)
+	}
+
+	var proximoCursor *modelos.Cursor
+	if paginacao.TemProximaPagina(len(publicacoes)) {
+		publicacoes = publicacoes[:paginacao.Limite]
+		ultima := publicacoes[len(publicacoes)-1]
+		proximoCursor = &modelos.Cursor{CriadoEm: ultima.CriadaEm, ID: ultima.ID}
+	}
+
+	if erro = repositorio.montarEntidades(publicacoes); erro != nil {
+		return nil, nil, erro
+	}
+
+	return publicacoes, proximoCursor, nil
+}
+
+// BuscarHashtagsEmAlta retorna as hashtags mais usadas em publicações criadas desde o instante informado.
+// Publicações ocultadas e de contas privadas não entram na contagem.
+func (repositorio Publicacoes) BuscarHashtagsEmAlta(desde time.Time, limite uint64) ([]modelos.HashtagEmAlta, error) {
+	linhas, erro := repositorio.db.Query(
+		`select h.hashtag, count(*) as total from publicacoes_hashtags h
+		inner join publicacoes p on p.id = h.publicacao_id
+		inner join usuarios u on u.id = p.autor_id
+		where h.criadaEm >= ?
+		and p.ocultaEm is null
+		and u.privado = false
+		group by h.hashtag
+		order by total desc, h.hashtag
+		limit ?`,
+		desde, limite,
+	)
+	if erro != nil {
+		return nil, erro
+	}
+	defer linhas.Close()
+
+	hashtags := make([]modelos.HashtagEmAlta, 0)
+
+	for linhas.Next() {
+		var hashtag modelos.HashtagEmAlta
+
+		if erro = linhas.Scan(&hashtag.Hashtag, &hashtag.Publicacoes); erro != nil {
+			return nil, erro
+		}
+
+		hashtags = append(hashtags, hashtag)
+	}
+
+	return hashtags, nil
+}
+
+// montarEntidades preenche as entidades de cada publicação, carregando as menções de todas de uma só vez
+func (repositorio Publicacoes) montarEntidades(publicacoes []modelos.Publicacao) error {
+	ids := make([]uint64, 0, len(publicacoes))
+	for _, publicacao := range publicacoes {
+		ids = append(ids, publicacao.ID)
+	}
+
+	mencoes, erro := repositorio.buscarMencoes(ids)
+	if erro != nil {
+		return erro
+	}
+
+	for i := range publicacoes {
+		publicacoes[i].MontarEntidades(mencoes[publicacoes[i].ID])
+	}
+
+	return nil
+}
+
+// buscarMencoes retorna as menções das publicações informadas, agrupadas pelo id da publicação
+func (repositorio Publicacoes) buscarMencoes(publicacaoIds []uint64) (map[uint64][]modelos.Mencao, error) {
+	mencoes := make(map[uint64][]modelos.Mencao)
+	if len(publicacaoIds) == 0 {
+		return mencoes, nil
+	}
+
+	parametros := make([]interface{}, 0, len(publicacaoIds))
+	for _, publicacaoId := range publicacaoIds {
+		parametros = append(parametros, publicacaoId)
+	}
+
+	linhas, erro := repositorio.db.Query(
+		`select publicacao_id, usuario_id, inicio, fim from mencoes
+		where publicacao_id in (?`+strings.Repeat(", ?", len(parametros)-1)+`)`,
+		parametros...,
+	)
+	if erro != nil {
+		return nil, erro
+	}
+	defer linhas.Close()
+
+	for linhas.Next() {
+		var publicacaoId uint64
+		var mencao modelos.Mencao
+
+		if erro = linhas.Scan(&publicacaoId, &mencao.UsuarioId, &mencao.Inicio, &mencao.Fim); erro != nil {
+			return nil, erro
+		}
+
+		mencoes[publicacaoId] = append(mencoes[publicacaoId], mencao)
+	}
+
+	return mencoes, nil
+}
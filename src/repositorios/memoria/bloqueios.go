@@ -0,0 +1,102 @@
+package memoria
+
+import (
+	"api/src/modelos"
+	"time"
+)
+
+// Bloquear registra o bloqueio e desfaz os vínculos de seguidor e os pedidos para seguir nos dois sentidos
+func (repositorio Usuarios) Bloquear(usuarioId, bloqueadoId uint64) error {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	_, usuarioExiste := repositorio.banco.usuarios[usuarioId]
+	_, bloqueadoExiste := repositorio.banco.usuarios[bloqueadoId]
+	if !usuarioExiste || !bloqueadoExiste {
+		return erroChaveEstrangeira
+	}
+
+	chave := relacao{usuarioId, bloqueadoId}
+	if _, existe := repositorio.banco.bloqueios[chave]; !existe {
+		repositorio.banco.bloqueios[chave] = time.Now()
+	}
+
+	delete(repositorio.banco.seguidores, relacao{usuarioId, bloqueadoId})
+	delete(repositorio.banco.seguidores, relacao{bloqueadoId, usuarioId})
+	delete(repositorio.banco.solicitacoes, relacao{usuarioId, bloqueadoId})
+	delete(repositorio.banco.solicitacoes, relacao{bloqueadoId, usuarioId})
+
+	return nil
+}
+
+// Desbloquear remove o bloqueio feito pelo usuário
+func (repositorio Usuarios) Desbloquear(usuarioId, bloqueadoId uint64) error {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	delete(repositorio.banco.bloqueios, relacao{usuarioId, bloqueadoId})
+	return nil
+}
+
+// Bloqueado indica se existe bloqueio entre os dois usuários, em qualquer sentido
+func (repositorio Usuarios) Bloqueado(usuarioId, outroUsuarioId uint64) (bool, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	return repositorio.bloqueado(usuarioId, outroUsuarioId), nil
+}
+
+// BuscarBloqueados retorna os usuários bloqueados pelo usuário, dos bloqueios mais recentes para os mais antigos
+func (repositorio Usuarios) BuscarBloqueados(usuarioId uint64, paginacao modelos.Paginacao) ([]modelos.Usuario, *modelos.Cursor, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	return repositorio.buscarRelacionados(repositorio.banco.bloqueios, paginacao, func(chave relacao) (uint64, bool) {
+		return chave.segundo, chave.primeiro == usuarioId
+	})
+}
+
+// Silenciar esconde as publicações do silenciado do feed do usuário
+func (repositorio Usuarios) Silenciar(usuarioId, silenciadoId uint64) error {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	_, usuarioExiste := repositorio.banco.usuarios[usuarioId]
+	_, silenciadoExiste := repositorio.banco.usuarios[silenciadoId]
+	if !usuarioExiste || !silenciadoExiste {
+		return erroChaveEstrangeira
+	}
+
+	chave := relacao{usuarioId, silenciadoId}
+	if _, existe := repositorio.banco.silenciados[chave]; !existe {
+		repositorio.banco.silenciados[chave] = time.Now()
+	}
+
+	return nil
+}
+
+// Dessilenciar volta a exibir as publicações do silenciado no feed do usuário
+func (repositorio Usuarios) Dessilenciar(usuarioId, silenciadoId uint64) error {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	delete(repositorio.banco.silenciados, relacao{usuarioId, silenciadoId})
+	return nil
+}
+
+// BuscarSilenciados retorna os usuários silenciados pelo usuário, dos mais recentes para os mais antigos
+func (repositorio Usuarios) BuscarSilenciados(usuarioId uint64, paginacao modelos.Paginacao) ([]modelos.Usuario, *modelos.Cursor, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	return repositorio.buscarRelacionados(repositorio.banco.silenciados, paginacao, func(chave relacao) (uint64, bool) {
+		return chave.segundo, chave.primeiro == usuarioId
+	})
+}
+
+// bloqueado verifica o bloqueio nos dois sentidos. Deve ser chamado com o banco travado.
+func (repositorio Usuarios) bloqueado(usuarioId, outroUsuarioId uint64) bool {
+	_, bloqueou := repositorio.banco.bloqueios[relacao{usuarioId, outroUsuarioId}]
+	_, foiBloqueado := repositorio.banco.bloqueios[relacao{outroUsuarioId, usuarioId}]
+	return bloqueou || foiBloqueado
+}
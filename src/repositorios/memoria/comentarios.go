@@ -0,0 +1,86 @@
+package memoria
+
+import (
+	"api/src/modelos"
+	"time"
+)
+
+// Comentarios é a implementação em memória de repositorios.RepositorioDeComentarios
+type Comentarios struct {
+	banco *banco
+}
+
+// Criar insere o comentário na publicação
+func (repositorio Comentarios) Criar(comentario modelos.Comentario) (uint64, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	_, publicacaoExiste := repositorio.banco.publicacoes[comentario.PublicacaoId]
+	_, autorExiste := repositorio.banco.usuarios[comentario.AutorId]
+	if !publicacaoExiste || !autorExiste {
+		return 0, erroChaveEstrangeira
+	}
+
+	comentario.ID = repositorio.banco.proximoId("comentarios")
+	comentario.CriadoEm = time.Now()
+	comentario.AutorNick = ""
+	repositorio.banco.comentarios[comentario.ID] = comentario
+
+	return comentario.ID, nil
+}
+
+// BuscarPorPublicacao retorna os comentários da publicação, do mais antigo para o mais recente
+func (repositorio Comentarios) BuscarPorPublicacao(publicacaoId uint64, paginacao modelos.Paginacao) ([]modelos.Comentario, *modelos.Cursor, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	comentarios := make([]modelos.Comentario, 0)
+	for _, comentario := range repositorio.banco.comentarios {
+		if comentario.PublicacaoId == publicacaoId {
+			comentario.AutorNick = repositorio.banco.usuarios[comentario.AutorId].Nick
+			comentarios = append(comentarios, comentario)
+		}
+	}
+
+	comentarios, proximoCursor := paginar(comentarios, func(comentario modelos.Comentario) (time.Time, uint64) {
+		return comentario.CriadoEm, comentario.ID
+	}, true, paginacao)
+
+	return comentarios, proximoCursor, nil
+}
+
+// BuscarPorId retorna o comentário. Comentário inexistente resulta em ID zero.
+func (repositorio Comentarios) BuscarPorId(comentarioId uint64) (modelos.Comentario, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	comentario, existe := repositorio.banco.comentarios[comentarioId]
+	if !existe {
+		return modelos.Comentario{}, nil
+	}
+
+	comentario.AutorNick = repositorio.banco.usuarios[comentario.AutorId].Nick
+	return comentario, nil
+}
+
+// Atualizar altera o conteúdo do comentário
+func (repositorio Comentarios) Atualizar(comentarioId uint64, comentario modelos.Comentario) error {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	if comentarioSalvo, existe := repositorio.banco.comentarios[comentarioId]; existe {
+		comentarioSalvo.Conteudo = comentario.Conteudo
+		repositorio.banco.comentarios[comentarioId] = comentarioSalvo
+	}
+
+	return nil
+}
+
+// Remover remove o comentário
+func (repositorio Comentarios) Remover(comentarioId uint64) error {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	delete(repositorio.banco.comentarios, comentarioId)
+	return nil
+}
@@ -0,0 +1,124 @@
+package memoria
+
+import (
+	"api/src/modelos"
+	"api/src/repositorios"
+	"time"
+)
+
+// Denuncias é a implementação em memória de repositorios.RepositorioDeDenuncias
+type Denuncias struct {
+	banco *banco
+}
+
+// Criar insere a denúncia, respeitando a unicidade por denunciante e publicação ou usuário denunciado
+func (repositorio Denuncias) Criar(denuncia modelos.Denuncia) (uint64, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	if _, existe := repositorio.banco.usuarios[denuncia.DenuncianteId]; !existe {
+		return 0, erroChaveEstrangeira
+	}
+
+	if denuncia.PublicacaoId != nil {
+		if _, existe := repositorio.banco.publicacoes[*denuncia.PublicacaoId]; !existe {
+			return 0, erroChaveEstrangeira
+		}
+	}
+
+	if denuncia.UsuarioId != nil {
+		if _, existe := repositorio.banco.usuarios[*denuncia.UsuarioId]; !existe {
+			return 0, erroChaveEstrangeira
+		}
+	}
+
+	for _, existente := range repositorio.banco.denuncias {
+		if existente.DenuncianteId != denuncia.DenuncianteId {
+			continue
+		}
+
+		if mesmoId(existente.PublicacaoId, denuncia.PublicacaoId) {
+			return 0, repositorios.ErroDeDuplicidade{Campo: "uk_denuncias_publicacao"}
+		}
+
+		if mesmoId(existente.UsuarioId, denuncia.UsuarioId) {
+			return 0, repositorios.ErroDeDuplicidade{Campo: "uk_denuncias_usuario"}
+		}
+	}
+
+	denuncia.ID = repositorio.banco.proximoId("denuncias")
+	denuncia.Situacao = modelos.DenunciaPendente
+	denuncia.ModeradorId = nil
+	denuncia.ModeradaEm = nil
+	denuncia.CriadaEm = time.Now()
+	repositorio.banco.denuncias[denuncia.ID] = denuncia
+
+	return denuncia.ID, nil
+}
+
+// ContarDenunciantes retorna quantos usuários distintos denunciaram a publicação, desconsiderando denúncias descartadas
+func (repositorio Denuncias) ContarDenunciantes(publicacaoId uint64) (uint64, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	denunciantes := make(map[uint64]bool)
+	for _, denuncia := range repositorio.banco.denuncias {
+		if mesmoId(denuncia.PublicacaoId, &publicacaoId) && denuncia.Situacao != modelos.DenunciaDescartada {
+			denunciantes[denuncia.DenuncianteId] = true
+		}
+	}
+
+	return uint64(len(denunciantes)), nil
+}
+
+// BuscarPorSituacao retorna a fila de moderação, das denúncias mais antigas para as mais recentes
+func (repositorio Denuncias) BuscarPorSituacao(situacao string, paginacao modelos.Paginacao) ([]modelos.Denuncia, *modelos.Cursor, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	denuncias := make([]modelos.Denuncia, 0)
+	for _, denuncia := range repositorio.banco.denuncias {
+		if denuncia.Situacao == situacao {
+			denuncias = append(denuncias, denuncia)
+		}
+	}
+
+	denuncias, proximoCursor := paginar(denuncias, func(denuncia modelos.Denuncia) (time.Time, uint64) {
+		return denuncia.CriadaEm, denuncia.ID
+	}, true, paginacao)
+
+	return denuncias, proximoCursor, nil
+}
+
+// BuscarPorId retorna a denúncia. Denúncia inexistente resulta em ID zero.
+func (repositorio Denuncias) BuscarPorId(denunciaId uint64) (modelos.Denuncia, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	return repositorio.banco.denuncias[denunciaId], nil
+}
+
+// Moderar registra a decisão do moderador sobre uma denúncia pendente.
+// Retorna false se a denúncia já havia sido moderada.
+func (repositorio Denuncias) Moderar(denunciaId, moderadorId uint64, situacao string) (bool, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	denuncia, existe := repositorio.banco.denuncias[denunciaId]
+	if !existe || denuncia.Situacao != modelos.DenunciaPendente {
+		return false, nil
+	}
+
+	agora := time.Now()
+	denuncia.Situacao = situacao
+	denuncia.ModeradorId = &moderadorId
+	denuncia.ModeradaEm = &agora
+	repositorio.banco.denuncias[denunciaId] = denuncia
+
+	return true, nil
+}
+
+// mesmoId compara duas colunas anuláveis como uma chave única do MySQL: nulos nunca são iguais
+func mesmoId(a, b *uint64) bool {
+	return a != nil && b != nil && *a == *b
+}
@@ -0,0 +1,67 @@
+package memoria
+
+import (
+	"api/src/modelos"
+	"slices"
+	"sort"
+	"time"
+)
+
+// BuscarPorHashtag retorna as publicações com a hashtag visíveis ao usuário logado, paginadas por (criadaEm, id)
+func (repositorio Publicacoes) BuscarPorHashtag(hashtag string, usuarioLogadoId uint64, paginacao modelos.Paginacao) ([]modelos.Publicacao, *modelos.Cursor, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	publicacoes := make([]modelos.Publicacao, 0)
+	for publicacaoId, hashtags := range repositorio.banco.hashtags {
+		publicacao := repositorio.banco.publicacoes[publicacaoId]
+		if !slices.Contains(hashtags, hashtag) || !repositorio.visivel(publicacao, usuarioLogadoId) {
+			continue
+		}
+
+		publicacoes = append(publicacoes, repositorio.completar(publicacao, usuarioLogadoId))
+	}
+
+	publicacoes, proximoCursor := paginar(publicacoes, chavePublicacao, false, paginacao)
+	return publicacoes, proximoCursor, nil
+}
+
+// BuscarHashtagsEmAlta conta as hashtags das publicações criadas desde o instante informado,
+// ignorando publicações ocultadas e de contas privadas
+func (repositorio Publicacoes) BuscarHashtagsEmAlta(desde time.Time, limite uint64) ([]modelos.HashtagEmAlta, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	totais := make(map[string]uint64)
+	for publicacaoId, hashtags := range repositorio.banco.hashtags {
+		publicacao := repositorio.banco.publicacoes[publicacaoId]
+		if _, oculta := repositorio.banco.ocultas[publicacaoId]; oculta || publicacao.CriadaEm.Before(desde) {
+			continue
+		}
+		if repositorio.banco.usuarios[publicacao.AuthorId].ContaPrivada() {
+			continue
+		}
+
+		for _, hashtag := range hashtags {
+			totais[hashtag]++
+		}
+	}
+
+	emAlta := make([]modelos.HashtagEmAlta, 0, len(totais))
+	for hashtag, total := range totais {
+		emAlta = append(emAlta, modelos.HashtagEmAlta{Hashtag: hashtag, Publicacoes: total})
+	}
+
+	sort.Slice(emAlta, func(i, j int) bool {
+		if emAlta[i].Publicacoes != emAlta[j].Publicacoes {
+			return emAlta[i].Publicacoes > emAlta[j].Publicacoes
+		}
+		return emAlta[i].Hashtag < emAlta[j].Hashtag
+	})
+
+	if uint64(len(emAlta)) > limite {
+		emAlta = emAlta[:limite]
+	}
+
+	return emAlta, nil
+}
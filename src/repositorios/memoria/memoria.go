@@ -0,0 +1,260 @@
+// Package memoria implementa os repositórios da API em memória, com a mesma semântica dos repositórios MySQL
+// (chaves únicas, exclusão em cascata, ordenação e paginação). Destina-se a testes com httptest,
+// dispensando um MySQL em execução:
+//
+//	r, _ := router.Gerar(memoria.NovosRepositorios())
+//	httptest.NewServer(r)
+package memoria
+
+import (
+	"api/src/modelos"
+	"api/src/repositorios"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// erroChaveEstrangeira simula a violação de chave estrangeira do MySQL
+var erroChaveEstrangeira = errors.New("registro referenciado não existe")
+
+// relacao identifica um par de ids, como (usuario_id, seguidor_id) ou (usuario_id, publicacao_id)
+type relacao struct {
+	primeiro uint64
+	segundo  uint64
+}
+
+// banco guarda o estado compartilhado por todos os repositórios em memória
+type banco struct {
+	mu sync.Mutex
+
+	ultimoId map[string]uint64
+
+	usuarios        map[uint64]modelos.Usuario
+	seguidores      map[relacao]time.Time // (usuario_id, seguidor_id)
+	bloqueios       map[relacao]time.Time // (usuario_id, bloqueado_id)
+	silenciados     map[relacao]time.Time // (usuario_id, silenciado_id)
+	solicitacoes    map[relacao]time.Time // (usuario_id, solicitante_id)
+	publicacoes     map[uint64]modelos.Publicacao
+	ocultas         map[uint64]time.Time        // publicacoes.ocultaEm
+	curtidas        map[relacao]time.Time       // (usuario_id, publicacao_id)
+	hashtags        map[uint64][]string         // publicacoes_hashtags, por publicação
+	mencoes         map[uint64][]modelos.Mencao // mencoes, por publicação
+	comentarios     map[uint64]modelos.Comentario
+	refreshTokens   map[uint64]modelos.RefreshToken
+	tokensRevogados map[string]time.Time
+	redefinicoes    map[uint64]modelos.TokenDeRedefinicao // tokens_redefinicao_senha
+	denuncias       map[uint64]modelos.Denuncia
+}
+
+// NovosRepositorios cria repositórios em memória que compartilham o mesmo estado, vazio
+func NovosRepositorios() repositorios.Repositorios {
+	banco := &banco{
+		ultimoId:        make(map[string]uint64),
+		usuarios:        make(map[uint64]modelos.Usuario),
+		seguidores:      make(map[relacao]time.Time),
+		bloqueios:       make(map[relacao]time.Time),
+		silenciados:     make(map[relacao]time.Time),
+		solicitacoes:    make(map[relacao]time.Time),
+		publicacoes:     make(map[uint64]modelos.Publicacao),
+		ocultas:         make(map[uint64]time.Time),
+		curtidas:        make(map[relacao]time.Time),
+		hashtags:        make(map[uint64][]string),
+		mencoes:         make(map[uint64][]modelos.Mencao),
+		comentarios:     make(map[uint64]modelos.Comentario),
+		refreshTokens:   make(map[uint64]modelos.RefreshToken),
+		tokensRevogados: make(map[string]time.Time),
+		redefinicoes:    make(map[uint64]modelos.TokenDeRedefinicao),
+		denuncias:       make(map[uint64]modelos.Denuncia),
+	}
+
+	return repositorios.Repositorios{
+		Usuarios:    &Usuarios{banco},
+		Publicacoes: &Publicacoes{banco},
+		Comentarios: &Comentarios{banco},
+		Tokens:      &Tokens{banco},
+		Denuncias:   &Denuncias{banco},
+		Saude:       &Saude{banco},
+	}
+}
+
+// proximoId simula o auto_increment da tabela informada
+func (banco *banco) proximoId(tabela string) uint64 {
+	banco.ultimoId[tabela]++
+	return banco.ultimoId[tabela]
+}
+
+// removerUsuario remove o usuário e tudo que depende dele (ON DELETE CASCADE)
+func (banco *banco) removerUsuario(usuarioId uint64) {
+	delete(banco.usuarios, usuarioId)
+
+	for _, relacoes := range []map[relacao]time.Time{banco.seguidores, banco.bloqueios, banco.silenciados, banco.solicitacoes} {
+		for chave := range relacoes {
+			if chave.primeiro == usuarioId || chave.segundo == usuarioId {
+				delete(relacoes, chave)
+			}
+		}
+	}
+
+	for id, publicacao := range banco.publicacoes {
+		if publicacao.AuthorId == usuarioId {
+			banco.removerPublicacao(id)
+		}
+	}
+
+	for chave := range banco.curtidas {
+		if chave.primeiro == usuarioId {
+			banco.descurtir(chave.primeiro, chave.segundo)
+		}
+	}
+
+	for publicacaoId, mencoes := range banco.mencoes {
+		restantes := make([]modelos.Mencao, 0, len(mencoes))
+		for _, mencao := range mencoes {
+			if mencao.UsuarioId != usuarioId {
+				restantes = append(restantes, mencao)
+			}
+		}
+		banco.mencoes[publicacaoId] = restantes
+	}
+
+	for id, comentario := range banco.comentarios {
+		if comentario.AutorId == usuarioId {
+			delete(banco.comentarios, id)
+		}
+	}
+
+	for id, refreshToken := range banco.refreshTokens {
+		if refreshToken.UsuarioId == usuarioId {
+			delete(banco.refreshTokens, id)
+		}
+	}
+
+	for id, token := range banco.redefinicoes {
+		if token.UsuarioId == usuarioId {
+			delete(banco.redefinicoes, id)
+		}
+	}
+
+	for id, denuncia := range banco.denuncias {
+		switch {
+		case denuncia.DenuncianteId == usuarioId, denuncia.UsuarioId != nil && *denuncia.UsuarioId == usuarioId:
+			delete(banco.denuncias, id)
+		case denuncia.ModeradorId != nil && *denuncia.ModeradorId == usuarioId:
+			denuncia.ModeradorId = nil
+			banco.denuncias[id] = denuncia
+		}
+	}
+}
+
+// removerPublicacao remove a publicação, suas curtidas, comentários, denúncias, hashtags e menções (ON DELETE CASCADE)
+func (banco *banco) removerPublicacao(publicacaoId uint64) {
+	delete(banco.publicacoes, publicacaoId)
+	delete(banco.ocultas, publicacaoId)
+	delete(banco.hashtags, publicacaoId)
+	delete(banco.mencoes, publicacaoId)
+
+	for id, denuncia := range banco.denuncias {
+		if denuncia.PublicacaoId != nil && *denuncia.PublicacaoId == publicacaoId {
+			delete(banco.denuncias, id)
+		}
+	}
+
+	for chave := range banco.curtidas {
+		if chave.segundo == publicacaoId {
+			delete(banco.curtidas, chave)
+		}
+	}
+
+	for id, comentario := range banco.comentarios {
+		if comentario.PublicacaoId == publicacaoId {
+			delete(banco.comentarios, id)
+		}
+	}
+}
+
+// gravarEntidades substitui as hashtags e menções da publicação, resolvendo os nicks mencionados
+// como o MySQL faria: sem diferenciar maiúsculas e descartando nicks inexistentes
+func (banco *banco) gravarEntidades(publicacaoId uint64, publicacao modelos.Publicacao) {
+	banco.hashtags[publicacaoId] = append([]string{}, publicacao.Hashtags...)
+
+	mencoes := make([]modelos.Mencao, 0, len(publicacao.Mencoes))
+	for _, mencao := range publicacao.Mencoes {
+		for _, usuario := range banco.usuarios {
+			if strings.EqualFold(usuario.Nick, mencao.Nick) {
+				mencao.UsuarioId = usuario.ID
+				mencoes = append(mencoes, mencao)
+				break
+			}
+		}
+	}
+	banco.mencoes[publicacaoId] = mencoes
+}
+
+func (banco *banco) descurtir(usuarioId, publicacaoId uint64) {
+	chave := relacao{usuarioId, publicacaoId}
+	if _, existe := banco.curtidas[chave]; !existe {
+		return
+	}
+
+	delete(banco.curtidas, chave)
+
+	if publicacao, existe := banco.publicacoes[publicacaoId]; existe && publicacao.Curtidas > 0 {
+		publicacao.Curtidas--
+		banco.publicacoes[publicacaoId] = publicacao
+	}
+}
+
+// paginar ordena os itens por (data, id), decrescente ou crescente, e aplica cursor e limite
+// da mesma forma que as consultas do MySQL
+func paginar[T any](itens []T, chave func(T) (time.Time, uint64), crescente bool, paginacao modelos.Paginacao) ([]T, *modelos.Cursor) {
+	antes := func(dataA time.Time, idA uint64, dataB time.Time, idB uint64) bool {
+		if !dataA.Equal(dataB) {
+			return dataA.Before(dataB)
+		}
+		return idA < idB
+	}
+
+	sort.Slice(itens, func(i, j int) bool {
+		dataI, idI := chave(itens[i])
+		dataJ, idJ := chave(itens[j])
+		if crescente {
+			return antes(dataI, idI, dataJ, idJ)
+		}
+		return antes(dataJ, idJ, dataI, idI)
+	})
+
+	pagina := make([]T, 0)
+	for _, item := range itens {
+		if paginacao.Cursor != nil {
+			data, id := chave(item)
+			if crescente && !antes(paginacao.Cursor.CriadoEm, paginacao.Cursor.ID, data, id) {
+				continue
+			}
+			if !crescente && !antes(data, id, paginacao.Cursor.CriadoEm, paginacao.Cursor.ID) {
+				continue
+			}
+		}
+
+		pagina = append(pagina, item)
+	}
+
+	if !paginacao.TemProximaPagina(len(pagina)) {
+		return pagina, nil
+	}
+
+	pagina = pagina[:paginacao.Limite]
+	data, id := chave(pagina[len(pagina)-1])
+	return pagina, &modelos.Cursor{CriadoEm: data, ID: id}
+}
+
+// Garante, em tempo de compilação, que as implementações em memória satisfazem as interfaces
+var (
+	_ repositorios.RepositorioDeUsuarios    = (*Usuarios)(nil)
+	_ repositorios.RepositorioDePublicacoes = (*Publicacoes)(nil)
+	_ repositorios.RepositorioDeComentarios = (*Comentarios)(nil)
+	_ repositorios.RepositorioDeTokens      = (*Tokens)(nil)
+	_ repositorios.RepositorioDeDenuncias   = (*Denuncias)(nil)
+	_ repositorios.RepositorioDeSaude       = (*Saude)(nil)
+)
@@ -0,0 +1,332 @@
+package memoria
+
+import (
+	"api/src/modelos"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Publicacoes é a implementação em memória de repositorios.RepositorioDePublicacoes
+type Publicacoes struct {
+	banco *banco
+}
+
+// Criar insere a publicação do autor informado
+func (repositorio Publicacoes) Criar(publicacao modelos.Publicacao) (uint64, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	if _, existe := repositorio.banco.usuarios[publicacao.AuthorId]; !existe {
+		return 0, erroChaveEstrangeira
+	}
+
+	publicacao.ID = repositorio.banco.proximoId("publicacoes")
+	publicacao.Curtidas = 0
+	publicacao.CriadaEm = time.Now()
+	publicacao.AuthorNick = ""
+	publicacao.CurtidoPorMim = false
+	publicacao.TotalComentarios = 0
+	repositorio.banco.gravarEntidades(publicacao.ID, publicacao)
+	publicacao.Hashtags = nil
+	publicacao.Mencoes = nil
+	repositorio.banco.publicacoes[publicacao.ID] = publicacao
+
+	return publicacao.ID, nil
+}
+
+// Buscar retorna o feed do usuário (publicações próprias e de quem ele segue, exceto as ocultadas
+// e as de usuários silenciados), paginado por (criadaEm, id)
+func (repositorio Publicacoes) Buscar(usuarioId uint64, paginacao modelos.Paginacao) ([]modelos.Publicacao, *modelos.Cursor, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	autores := map[uint64]bool{usuarioId: true}
+	for chave := range repositorio.banco.seguidores {
+		if chave.segundo == usuarioId {
+			autores[chave.primeiro] = true
+		}
+	}
+
+	publicacoes := make([]modelos.Publicacao, 0)
+	for _, publicacao := range repositorio.banco.publicacoes {
+		if _, oculta := repositorio.banco.ocultas[publicacao.ID]; oculta {
+			continue
+		}
+
+		if _, silenciado := repositorio.banco.silenciados[relacao{usuarioId, publicacao.AuthorId}]; silenciado {
+			continue
+		}
+
+		if autores[publicacao.AuthorId] {
+			publicacoes = append(publicacoes, repositorio.completar(publicacao, usuarioId))
+		}
+	}
+
+	publicacoes, proximoCursor := paginar(publicacoes, chavePublicacao, false, paginacao)
+	return publicacoes, proximoCursor, nil
+}
+
+// BuscarPorId retorna a publicação com o nick do autor. Publicação inexistente resulta em ID zero.
+func (repositorio Publicacoes) BuscarPorId(publicacaoId uint64) (modelos.Publicacao, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	publicacao, existe := repositorio.banco.publicacoes[publicacaoId]
+	if !existe {
+		return modelos.Publicacao{}, nil
+	}
+
+	publicacao.AuthorNick = repositorio.banco.usuarios[publicacao.AuthorId].Nick
+	publicacao.MontarEntidades(repositorio.banco.mencoes[publicacao.ID])
+	if ocultaEm, oculta := repositorio.banco.ocultas[publicacao.ID]; oculta {
+		publicacao.OcultaEm = &ocultaEm
+	}
+	return publicacao, nil
+}
+
+// Atualizar altera título e conteúdo da publicação e regrava suas hashtags e menções
+func (repositorio Publicacoes) Atualizar(publicacaoId uint64, publicacao modelos.Publicacao) error {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	if publicacaoSalva, existe := repositorio.banco.publicacoes[publicacaoId]; existe {
+		publicacaoSalva.Titulo = publicacao.Titulo
+		publicacaoSalva.Conteudo = publicacao.Conteudo
+		repositorio.banco.publicacoes[publicacaoId] = publicacaoSalva
+		repositorio.banco.gravarEntidades(publicacaoId, publicacao)
+	}
+
+	return nil
+}
+
+// RemoverPublicacao remove a publicação e, em cascata, suas curtidas e comentários
+func (repositorio Publicacoes) RemoverPublicacao(publicacaoId uint64) error {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	repositorio.banco.removerPublicacao(publicacaoId)
+	return nil
+}
+
+// BuscarPorUsuario retorna as publicações do usuário, paginadas por (criadaEm, id).
+// Publicações ocultadas só aparecem para o próprio autor.
+func (repositorio Publicacoes) BuscarPorUsuario(usuarioId, usuarioLogadoId uint64, paginacao modelos.Paginacao) ([]modelos.Publicacao, *modelos.Cursor, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	publicacoes := make([]modelos.Publicacao, 0)
+	for _, publicacao := range repositorio.banco.publicacoes {
+		if _, oculta := repositorio.banco.ocultas[publicacao.ID]; oculta && usuarioLogadoId != usuarioId {
+			continue
+		}
+
+		if publicacao.AuthorId == usuarioId {
+			publicacoes = append(publicacoes, repositorio.completar(publicacao, usuarioLogadoId))
+		}
+	}
+
+	publicacoes, proximoCursor := paginar(publicacoes, chavePublicacao, false, paginacao)
+	return publicacoes, proximoCursor, nil
+}
+
+// Pesquisar busca os termos no título e no conteúdo das publicações visíveis ao usuário logado.
+// A relevância é a quantidade de palavras que coincidem com os termos, e a paginação segue (relevancia, criadaEm, id).
+func (repositorio Publicacoes) Pesquisar(busca string, usuarioLogadoId uint64, paginacao modelos.Paginacao) ([]modelos.PublicacaoEncontrada, *modelos.Cursor, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	termos := make(map[string]bool)
+	for _, termo := range modelos.TermosDaBusca(busca) {
+		termos[termo] = true
+	}
+
+	encontradas := make([]modelos.PublicacaoEncontrada, 0)
+	for _, publicacao := range repositorio.banco.publicacoes {
+		if !repositorio.visivel(publicacao, usuarioLogadoId) {
+			continue
+		}
+
+		var relevancia float64
+		for _, palavra := range strings.FieldsFunc(strings.ToLower(publicacao.Titulo+" "+publicacao.Conteudo), separadorDePalavras) {
+			if termos[palavra] {
+				relevancia++
+			}
+		}
+
+		if relevancia > 0 {
+			encontradas = append(encontradas, modelos.PublicacaoEncontrada{
+				Publicacao: repositorio.completar(publicacao, usuarioLogadoId),
+				Relevancia: relevancia,
+			})
+		}
+	}
+
+	sort.Slice(encontradas, func(i, j int) bool {
+		return anteriorNaBusca(encontradas[i], encontradas[j].Relevancia, encontradas[j].CriadaEm, encontradas[j].ID)
+	})
+
+	if paginacao.Cursor != nil {
+		restantes := make([]modelos.PublicacaoEncontrada, 0)
+		for _, encontrada := range encontradas {
+			cursor := paginacao.Cursor
+			if encontrada.ID != cursor.ID && !anteriorNaBusca(encontrada, cursor.Relevancia, cursor.CriadoEm, cursor.ID) {
+				restantes = append(restantes, encontrada)
+			}
+		}
+		encontradas = restantes
+	}
+
+	var proximoCursor *modelos.Cursor
+	if paginacao.TemProximaPagina(len(encontradas)) {
+		encontradas = encontradas[:paginacao.Limite]
+		ultima := encontradas[len(encontradas)-1]
+		proximoCursor = &modelos.Cursor{Relevancia: ultima.Relevancia, CriadoEm: ultima.CriadaEm, ID: ultima.ID}
+	}
+
+	return encontradas, proximoCursor, nil
+}
+
+// Curtir registra a curtida do usuário, ignorando curtidas repetidas
+func (repositorio Publicacoes) Curtir(usuarioId, publicacaoId uint64) error {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	publicacao, publicacaoExiste := repositorio.banco.publicacoes[publicacaoId]
+	_, usuarioExiste := repositorio.banco.usuarios[usuarioId]
+	if !publicacaoExiste || !usuarioExiste {
+		return erroChaveEstrangeira
+	}
+
+	chave := relacao{usuarioId, publicacaoId}
+	if _, existe := repositorio.banco.curtidas[chave]; existe {
+		return nil
+	}
+
+	repositorio.banco.curtidas[chave] = time.Now()
+	publicacao.Curtidas++
+	repositorio.banco.publicacoes[publicacaoId] = publicacao
+
+	return nil
+}
+
+// Descurtir remove a curtida do usuário, caso exista
+func (repositorio Publicacoes) Descurtir(usuarioId, publicacaoId uint64) error {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	repositorio.banco.descurtir(usuarioId, publicacaoId)
+	return nil
+}
+
+// BuscarCurtidas retorna os usuários que curtiram a publicação, das curtidas mais recentes para as mais antigas
+func (repositorio Publicacoes) BuscarCurtidas(publicacaoId uint64, paginacao modelos.Paginacao) ([]modelos.Usuario, *modelos.Cursor, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	type curtida struct {
+		usuario modelos.Usuario
+		em      time.Time
+	}
+
+	curtidas := make([]curtida, 0)
+	for chave, em := range repositorio.banco.curtidas {
+		if chave.segundo == publicacaoId {
+			usuario := repositorio.banco.usuarios[chave.primeiro]
+			curtidas = append(curtidas, curtida{
+				usuario: modelos.Usuario{ID: usuario.ID, Nome: usuario.Nome, Nick: usuario.Nick},
+				em:      em,
+			})
+		}
+	}
+
+	curtidas, proximoCursor := paginar(curtidas, func(item curtida) (time.Time, uint64) {
+		return item.em, item.usuario.ID
+	}, false, paginacao)
+
+	usuarios := make([]modelos.Usuario, 0, len(curtidas))
+	for _, item := range curtidas {
+		usuarios = append(usuarios, item.usuario)
+	}
+
+	return usuarios, proximoCursor, nil
+}
+
+// Ocultar retira a publicação dos feeds, mantendo a data da primeira ocultação
+func (repositorio Publicacoes) Ocultar(publicacaoId uint64) error {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	if _, existe := repositorio.banco.publicacoes[publicacaoId]; !existe {
+		return nil
+	}
+
+	if _, oculta := repositorio.banco.ocultas[publicacaoId]; !oculta {
+		repositorio.banco.ocultas[publicacaoId] = time.Now()
+	}
+
+	return nil
+}
+
+// Reexibir devolve aos feeds uma publicação ocultada
+func (repositorio Publicacoes) Reexibir(publicacaoId uint64) error {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	delete(repositorio.banco.ocultas, publicacaoId)
+	return nil
+}
+
+// visivel aplica as regras da busca e das hashtags: a publicação não pode estar oculta, não pode haver
+// bloqueio entre o autor e o usuário logado e contas privadas só aparecem para seus seguidores.
+// Deve ser chamado com o banco travado.
+func (repositorio Publicacoes) visivel(publicacao modelos.Publicacao, usuarioLogadoId uint64) bool {
+	if _, oculta := repositorio.banco.ocultas[publicacao.ID]; oculta {
+		return false
+	}
+
+	_, bloqueou := repositorio.banco.bloqueios[relacao{usuarioLogadoId, publicacao.AuthorId}]
+	_, foiBloqueado := repositorio.banco.bloqueios[relacao{publicacao.AuthorId, usuarioLogadoId}]
+	if bloqueou || foiBloqueado {
+		return false
+	}
+
+	_, segue := repositorio.banco.seguidores[relacao{publicacao.AuthorId, usuarioLogadoId}]
+	return !repositorio.banco.usuarios[publicacao.AuthorId].ContaPrivada() || publicacao.AuthorId == usuarioLogadoId || segue
+}
+
+// completar preenche os campos calculados nas consultas de listagem do MySQL
+func (repositorio Publicacoes) completar(publicacao modelos.Publicacao, usuarioLogadoId uint64) modelos.Publicacao {
+	publicacao.AuthorNick = repositorio.banco.usuarios[publicacao.AuthorId].Nick
+	publicacao.MontarEntidades(repositorio.banco.mencoes[publicacao.ID])
+	_, publicacao.CurtidoPorMim = repositorio.banco.curtidas[relacao{usuarioLogadoId, publicacao.ID}]
+
+	publicacao.TotalComentarios = 0
+	for _, comentario := range repositorio.banco.comentarios {
+		if comentario.PublicacaoId == publicacao.ID {
+			publicacao.TotalComentarios++
+		}
+	}
+
+	return publicacao
+}
+
+// anteriorNaBusca indica se a publicação vem antes da posição informada na ordenação da busca (relevancia, criadaEm, id) decrescente
+func anteriorNaBusca(publicacao modelos.PublicacaoEncontrada, relevancia float64, criadaEm time.Time, id uint64) bool {
+	if publicacao.Relevancia != relevancia {
+		return publicacao.Relevancia > relevancia
+	}
+	if !publicacao.CriadaEm.Equal(criadaEm) {
+		return publicacao.CriadaEm.After(criadaEm)
+	}
+	return publicacao.ID > id
+}
+
+func separadorDePalavras(caractere rune) bool {
+	return !unicode.IsLetter(caractere) && !unicode.IsDigit(caractere)
+}
+
+func chavePublicacao(publicacao modelos.Publicacao) (time.Time, uint64) {
+	return publicacao.CriadaEm, publicacao.ID
+}
@@ -0,0 +1,18 @@
+package memoria
+
+import "context"
+
+// Saude é a implementação em memória de repositorios.RepositorioDeSaude: o banco está sempre disponível
+type Saude struct {
+	banco *banco
+}
+
+// Pingar nunca falha, pois não há conexão a verificar
+func (repositorio Saude) Pingar(ctx context.Context) error {
+	return nil
+}
+
+// MigracoesPendentes é sempre zero, pois o esquema em memória não é versionado
+func (repositorio Saude) MigracoesPendentes(ctx context.Context) (int, error) {
+	return 0, nil
+}
@@ -0,0 +1,76 @@
+package memoria
+
+import (
+	"api/src/modelos"
+	"time"
+)
+
+// Segue indica se o seguidor segue o usuário
+func (repositorio Usuarios) Segue(usuarioId, seguidorId uint64) (bool, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	_, segue := repositorio.banco.seguidores[relacao{usuarioId, seguidorId}]
+	return segue, nil
+}
+
+// SolicitarSeguir registra o pedido do solicitante para seguir a conta privada do usuário, ignorando pedidos repetidos
+func (repositorio Usuarios) SolicitarSeguir(usuarioId, solicitanteId uint64) error {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	_, usuarioExiste := repositorio.banco.usuarios[usuarioId]
+	_, solicitanteExiste := repositorio.banco.usuarios[solicitanteId]
+	if !usuarioExiste || !solicitanteExiste {
+		return erroChaveEstrangeira
+	}
+
+	chave := relacao{usuarioId, solicitanteId}
+	if _, existe := repositorio.banco.solicitacoes[chave]; !existe {
+		repositorio.banco.solicitacoes[chave] = time.Now()
+	}
+
+	return nil
+}
+
+// BuscarSolicitacoes retorna os pedidos para seguir recebidos pelo usuário, dos mais recentes para os mais antigos
+func (repositorio Usuarios) BuscarSolicitacoes(usuarioId uint64, paginacao modelos.Paginacao) ([]modelos.Usuario, *modelos.Cursor, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	return repositorio.buscarRelacionados(repositorio.banco.solicitacoes, paginacao, func(chave relacao) (uint64, bool) {
+		return chave.segundo, chave.primeiro == usuarioId
+	})
+}
+
+// AceitarSolicitacao transforma o pedido pendente em seguidor. Retorna false se não havia pedido.
+func (repositorio Usuarios) AceitarSolicitacao(usuarioId, solicitanteId uint64) (bool, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	chave := relacao{usuarioId, solicitanteId}
+	if _, existe := repositorio.banco.solicitacoes[chave]; !existe {
+		return false, nil
+	}
+
+	delete(repositorio.banco.solicitacoes, chave)
+	if _, existe := repositorio.banco.seguidores[chave]; !existe {
+		repositorio.banco.seguidores[chave] = time.Now()
+	}
+
+	return true, nil
+}
+
+// RemoverSolicitacao apaga o pedido pendente. Retorna false se não havia pedido.
+func (repositorio Usuarios) RemoverSolicitacao(usuarioId, solicitanteId uint64) (bool, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	chave := relacao{usuarioId, solicitanteId}
+	if _, existe := repositorio.banco.solicitacoes[chave]; !existe {
+		return false, nil
+	}
+
+	delete(repositorio.banco.solicitacoes, chave)
+	return true, nil
+}
@@ -0,0 +1,178 @@
+package memoria
+
+import (
+	"api/src/modelos"
+	"errors"
+	"time"
+)
+
+// Tokens é a implementação em memória de repositorios.RepositorioDeTokens
+type Tokens struct {
+	banco *banco
+}
+
+// CriarRefreshToken guarda o refresh token emitido para o usuário
+func (repositorio Tokens) CriarRefreshToken(refreshToken modelos.RefreshToken) error {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	if _, existe := repositorio.banco.usuarios[refreshToken.UsuarioId]; !existe {
+		return erroChaveEstrangeira
+	}
+
+	for _, existente := range repositorio.banco.refreshTokens {
+		if existente.TokenHash == refreshToken.TokenHash {
+			return errors.New("refresh token duplicado")
+		}
+	}
+
+	refreshToken.ID = repositorio.banco.proximoId("refresh_tokens")
+	refreshToken.RevogadoEm = nil
+	repositorio.banco.refreshTokens[refreshToken.ID] = refreshToken
+
+	return nil
+}
+
+// BuscarRefreshToken retorna o refresh token dado o hash. Token inexistente resulta em ID zero.
+func (repositorio Tokens) BuscarRefreshToken(tokenHash string) (modelos.RefreshToken, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	for _, refreshToken := range repositorio.banco.refreshTokens {
+		if refreshToken.TokenHash == tokenHash {
+			return refreshToken, nil
+		}
+	}
+
+	return modelos.RefreshToken{}, nil
+}
+
+// RevogarRefreshToken marca o refresh token como revogado; retorna false se já estava revogado
+func (repositorio Tokens) RevogarRefreshToken(refreshTokenId uint64) (bool, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	refreshToken, existe := repositorio.banco.refreshTokens[refreshTokenId]
+	if !existe || refreshToken.RevogadoEm != nil {
+		return false, nil
+	}
+
+	agora := time.Now()
+	refreshToken.RevogadoEm = &agora
+	repositorio.banco.refreshTokens[refreshTokenId] = refreshToken
+
+	return true, nil
+}
+
+// RevogarAcesso inclui o token de acesso (jti) na lista de revogados até sua expiração
+func (repositorio Tokens) RevogarAcesso(jti string, expiraEm time.Time) error {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	if _, existe := repositorio.banco.tokensRevogados[jti]; !existe {
+		repositorio.banco.tokensRevogados[jti] = expiraEm
+	}
+
+	return nil
+}
+
+// RevogarSessoesDoUsuario revoga os refresh tokens ativos do usuário e os tokens de acesso emitidos com eles.
+// Os tokens de acesso dos refresh tokens já revogados entraram na lista na renovação ou no logout.
+func (repositorio Tokens) RevogarSessoesDoUsuario(usuarioId uint64, acessoExpiraEm time.Time) error {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	repositorio.revogarSessoes(usuarioId, acessoExpiraEm)
+	return nil
+}
+
+// revogarSessoes revoga os refresh tokens ativos do usuário e os tokens de acesso emitidos com eles.
+// Deve ser chamado com o banco travado.
+func (repositorio Tokens) revogarSessoes(usuarioId uint64, acessoExpiraEm time.Time) {
+	agora := time.Now()
+	for id, refreshToken := range repositorio.banco.refreshTokens {
+		if refreshToken.UsuarioId != usuarioId || refreshToken.RevogadoEm != nil {
+			continue
+		}
+
+		if _, existe := repositorio.banco.tokensRevogados[refreshToken.AcessoJti]; !existe {
+			repositorio.banco.tokensRevogados[refreshToken.AcessoJti] = acessoExpiraEm
+		}
+
+		refreshToken.RevogadoEm = &agora
+		repositorio.banco.refreshTokens[id] = refreshToken
+	}
+}
+
+// AcessoRevogado indica se o token de acesso (jti) está na lista de revogados e ainda não expirou
+func (repositorio Tokens) AcessoRevogado(jti string) (bool, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	expiraEm, existe := repositorio.banco.tokensRevogados[jti]
+	return existe && time.Now().Before(expiraEm), nil
+}
+
+// CriarTokenDeRedefinicao guarda o token de redefinição de senha emitido para o usuário
+func (repositorio Tokens) CriarTokenDeRedefinicao(token modelos.TokenDeRedefinicao) error {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	if _, existe := repositorio.banco.usuarios[token.UsuarioId]; !existe {
+		return erroChaveEstrangeira
+	}
+
+	for _, existente := range repositorio.banco.redefinicoes {
+		if existente.TokenHash == token.TokenHash {
+			return errors.New("token de redefinição duplicado")
+		}
+	}
+
+	token.ID = repositorio.banco.proximoId("tokens_redefinicao_senha")
+	token.UsadoEm = nil
+	repositorio.banco.redefinicoes[token.ID] = token
+
+	return nil
+}
+
+// BuscarTokenDeRedefinicao retorna o token de redefinição dado o hash. Token inexistente resulta em ID zero.
+func (repositorio Tokens) BuscarTokenDeRedefinicao(tokenHash string) (modelos.TokenDeRedefinicao, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	for _, token := range repositorio.banco.redefinicoes {
+		if token.TokenHash == tokenHash {
+			return token, nil
+		}
+	}
+
+	return modelos.TokenDeRedefinicao{}, nil
+}
+
+// RedefinirSenha grava a nova senha, marca o token como usado, invalida os demais tokens pendentes do usuário
+// e encerra suas sessões, de uma só vez; retorna false se o token já havia sido usado
+func (repositorio Tokens) RedefinirSenha(token modelos.TokenDeRedefinicao, senhaComHash string, acessoExpiraEm time.Time) (bool, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	salvo, existe := repositorio.banco.redefinicoes[token.ID]
+	if !existe || salvo.UsadoEm != nil {
+		return false, nil
+	}
+
+	agora := time.Now()
+	for id, pendente := range repositorio.banco.redefinicoes {
+		if pendente.UsuarioId == salvo.UsuarioId && pendente.UsadoEm == nil {
+			pendente.UsadoEm = &agora
+			repositorio.banco.redefinicoes[id] = pendente
+		}
+	}
+
+	if usuario, existe := repositorio.banco.usuarios[salvo.UsuarioId]; existe {
+		usuario.Senha = senhaComHash
+		repositorio.banco.usuarios[salvo.UsuarioId] = usuario
+	}
+	repositorio.revogarSessoes(salvo.UsuarioId, acessoExpiraEm)
+
+	return true, nil
+}
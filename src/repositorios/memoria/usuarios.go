@@ -0,0 +1,387 @@
+package memoria
+
+import (
+	"api/src/modelos"
+	"api/src/repositorios"
+	"strings"
+	"time"
+)
+
+// Usuarios é a implementação em memória de repositorios.RepositorioDeUsuarios
+type Usuarios struct {
+	banco *banco
+}
+
+// Criar insere o usuário, respeitando a unicidade de nick e email
+func (repositorio Usuarios) Criar(usuario modelos.Usuario) (uint64, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	if erro := repositorio.verificarUnicidade(0, usuario); erro != nil {
+		return 0, erro
+	}
+
+	usuario.ID = repositorio.banco.proximoId("usuarios")
+	usuario.CriadoEm = time.Now()
+	usuario.Papel = modelos.PapelUsuario
+	usuario.SuspensoEm = nil
+	usuario.VerificadoEm = nil
+	privado := usuario.ContaPrivada()
+	usuario.Privado = &privado
+	repositorio.banco.usuarios[usuario.ID] = usuario
+
+	return usuario.ID, nil
+}
+
+// Buscar retorna usuários cujo nome ou nick contém o texto informado, exceto os que têm bloqueio
+// em relação ao usuário logado, paginados por (criadoEm, id)
+func (repositorio Usuarios) Buscar(nomeOuNick string, usuarioLogadoId uint64, paginacao modelos.Paginacao) ([]modelos.Usuario, *modelos.Cursor, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	nomeOuNick = strings.ToLower(nomeOuNick)
+
+	usuarios := make([]modelos.Usuario, 0)
+	for _, usuario := range repositorio.banco.usuarios {
+		if repositorio.bloqueado(usuarioLogadoId, usuario.ID) {
+			continue
+		}
+
+		if strings.Contains(strings.ToLower(usuario.Nome), nomeOuNick) ||
+			strings.Contains(strings.ToLower(usuario.Nick), nomeOuNick) {
+			usuarios = append(usuarios, semSenha(usuario))
+		}
+	}
+
+	usuarios, proximoCursor := paginar(usuarios, func(usuario modelos.Usuario) (time.Time, uint64) {
+		return usuario.CriadoEm, usuario.ID
+	}, false, paginacao)
+
+	return usuarios, proximoCursor, nil
+}
+
+// BuscarPorId retorna o usuário, sem a senha. Usuário inexistente resulta em ID zero.
+func (repositorio Usuarios) BuscarPorId(usuarioId uint64) (modelos.Usuario, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	usuario, existe := repositorio.banco.usuarios[usuarioId]
+	if !existe {
+		return modelos.Usuario{}, nil
+	}
+
+	return semSenha(usuario), nil
+}
+
+// Atualizar altera nome, nick, email e privacidade do usuário, respeitando a unicidade de nick e email.
+// A troca de email desfaz a verificação do email anterior.
+func (repositorio Usuarios) Atualizar(usuarioId uint64, usuario modelos.Usuario) error {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	if erro := repositorio.verificarUnicidade(usuarioId, usuario); erro != nil {
+		return erro
+	}
+
+	usuarioSalvo, existe := repositorio.banco.usuarios[usuarioId]
+	if !existe {
+		return nil
+	}
+
+	if usuarioSalvo.Email != usuario.Email {
+		usuarioSalvo.VerificadoEm = nil
+	}
+
+	usuarioSalvo.Nome = usuario.Nome
+	usuarioSalvo.Nick = usuario.Nick
+	usuarioSalvo.Email = usuario.Email
+	if usuario.Privado != nil {
+		privado := *usuario.Privado
+		usuarioSalvo.Privado = &privado
+	}
+	repositorio.banco.usuarios[usuarioId] = usuarioSalvo
+
+	return nil
+}
+
+// RemoverUsuario remove o usuário e, em cascata, seus dados
+func (repositorio Usuarios) RemoverUsuario(usuarioId uint64) error {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	repositorio.banco.removerUsuario(usuarioId)
+	return nil
+}
+
+// NickEmUso indica se já existe usuário cadastrado com o nick informado
+func (repositorio Usuarios) NickEmUso(nick string) (bool, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	for _, usuario := range repositorio.banco.usuarios {
+		if strings.EqualFold(usuario.Nick, nick) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// EmailEmUso indica se já existe usuário cadastrado com o email informado
+func (repositorio Usuarios) EmailEmUso(email string) (bool, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	for _, usuario := range repositorio.banco.usuarios {
+		if strings.EqualFold(usuario.Email, email) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// BuscarPorEmail retorna id, email, senha (hash), data de criação, papel, suspensão e verificação do usuário
+func (repositorio Usuarios) BuscarPorEmail(email string) (modelos.Usuario, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	for _, usuario := range repositorio.banco.usuarios {
+		if strings.EqualFold(usuario.Email, email) {
+			return modelos.Usuario{
+				ID:           usuario.ID,
+				Email:        usuario.Email,
+				Senha:        usuario.Senha,
+				CriadoEm:     usuario.CriadoEm,
+				Papel:        usuario.Papel,
+				SuspensoEm:   usuario.SuspensoEm,
+				VerificadoEm: usuario.VerificadoEm,
+			}, nil
+		}
+	}
+
+	return modelos.Usuario{}, nil
+}
+
+// Seguir registra o par (usuario_id, seguidor_id), ignorando pares já existentes
+func (repositorio Usuarios) Seguir(usuarioId, seguidorId uint64) error {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	_, usuarioExiste := repositorio.banco.usuarios[usuarioId]
+	_, seguidorExiste := repositorio.banco.usuarios[seguidorId]
+	if !usuarioExiste || !seguidorExiste {
+		return erroChaveEstrangeira
+	}
+
+	chave := relacao{usuarioId, seguidorId}
+	if _, existe := repositorio.banco.seguidores[chave]; !existe {
+		repositorio.banco.seguidores[chave] = time.Now()
+	}
+
+	return nil
+}
+
+// PararDeSeguir remove o par (usuario_id, seguidor_id)
+func (repositorio Usuarios) PararDeSeguir(usuarioId, seguidorId uint64) error {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	delete(repositorio.banco.seguidores, relacao{usuarioId, seguidorId})
+	return nil
+}
+
+// BuscarSeguidores retorna os seguidores do usuário, dos mais recentes para os mais antigos
+func (repositorio Usuarios) BuscarSeguidores(usuarioId uint64, paginacao modelos.Paginacao) ([]modelos.Usuario, *modelos.Cursor, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	return repositorio.buscarRelacionados(repositorio.banco.seguidores, paginacao, func(chave relacao) (uint64, bool) {
+		return chave.segundo, chave.primeiro == usuarioId
+	})
+}
+
+// BuscarSeguindo retorna os usuários seguidos pelo usuário, dos mais recentes para os mais antigos
+func (repositorio Usuarios) BuscarSeguindo(usuarioId uint64, paginacao modelos.Paginacao) ([]modelos.Usuario, *modelos.Cursor, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	return repositorio.buscarRelacionados(repositorio.banco.seguidores, paginacao, func(chave relacao) (uint64, bool) {
+		return chave.primeiro, chave.segundo == usuarioId
+	})
+}
+
+// BuscarSenha retorna o hash da senha do usuário
+func (repositorio Usuarios) BuscarSenha(usuarioId uint64) (string, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	return repositorio.banco.usuarios[usuarioId].Senha, nil
+}
+
+// AtualizarSenha troca o hash da senha do usuário
+func (repositorio Usuarios) AtualizarSenha(usuarioId uint64, senhaComHash string) error {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	if usuario, existe := repositorio.banco.usuarios[usuarioId]; existe {
+		usuario.Senha = senhaComHash
+		repositorio.banco.usuarios[usuarioId] = usuario
+	}
+
+	return nil
+}
+
+// BuscarSituacao retorna id, papel e suspensão do usuário. Usuário inexistente resulta em ID zero.
+func (repositorio Usuarios) BuscarSituacao(usuarioId uint64) (modelos.Usuario, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	usuario, existe := repositorio.banco.usuarios[usuarioId]
+	if !existe {
+		return modelos.Usuario{}, nil
+	}
+
+	return modelos.Usuario{ID: usuario.ID, Papel: usuario.Papel, SuspensoEm: usuario.SuspensoEm}, nil
+}
+
+// DefinirPapel altera o papel do usuário. Não faz parte de repositorios.RepositorioDeUsuarios, pois a API
+// não promove usuários (no MySQL a promoção é feita direto no banco): existe para os testes.
+func (repositorio Usuarios) DefinirPapel(usuarioId uint64, papel string) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	if usuario, existe := repositorio.banco.usuarios[usuarioId]; existe {
+		usuario.Papel = papel
+		repositorio.banco.usuarios[usuarioId] = usuario
+	}
+}
+
+// BuscarContas retorna todas as contas, com papel e suspensão, paginadas por (criadoEm, id)
+func (repositorio Usuarios) BuscarContas(paginacao modelos.Paginacao) ([]modelos.Usuario, *modelos.Cursor, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	usuarios := make([]modelos.Usuario, 0, len(repositorio.banco.usuarios))
+	for _, usuario := range repositorio.banco.usuarios {
+		conta := semSenha(usuario)
+		conta.Papel = usuario.Papel
+		conta.SuspensoEm = usuario.SuspensoEm
+		usuarios = append(usuarios, conta)
+	}
+
+	usuarios, proximoCursor := paginar(usuarios, func(usuario modelos.Usuario) (time.Time, uint64) {
+		return usuario.CriadoEm, usuario.ID
+	}, false, paginacao)
+
+	return usuarios, proximoCursor, nil
+}
+
+// Suspender marca a conta do usuário como suspensa, mantendo a data da primeira suspensão
+func (repositorio Usuarios) Suspender(usuarioId uint64) error {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	if usuario, existe := repositorio.banco.usuarios[usuarioId]; existe && usuario.SuspensoEm == nil {
+		agora := time.Now()
+		usuario.SuspensoEm = &agora
+		repositorio.banco.usuarios[usuarioId] = usuario
+	}
+
+	return nil
+}
+
+// Reativar remove a suspensão da conta do usuário
+func (repositorio Usuarios) Reativar(usuarioId uint64) error {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	if usuario, existe := repositorio.banco.usuarios[usuarioId]; existe {
+		usuario.SuspensoEm = nil
+		repositorio.banco.usuarios[usuarioId] = usuario
+	}
+
+	return nil
+}
+
+// VerificarEmail marca o email do usuário como confirmado, somente se ele ainda for o email da conta
+// e não tiver sido verificado
+func (repositorio Usuarios) VerificarEmail(usuarioId uint64, email string) (bool, error) {
+	repositorio.banco.mu.Lock()
+	defer repositorio.banco.mu.Unlock()
+
+	usuario, existe := repositorio.banco.usuarios[usuarioId]
+	if !existe || usuario.Email != email || usuario.VerificadoEm != nil {
+		return false, nil
+	}
+
+	agora := time.Now()
+	usuario.VerificadoEm = &agora
+	repositorio.banco.usuarios[usuarioId] = usuario
+
+	return true, nil
+}
+
+// verificarUnicidade simula as chaves únicas de nick e email, na mesma ordem de verificação do MySQL
+func (repositorio Usuarios) verificarUnicidade(usuarioId uint64, usuario modelos.Usuario) error {
+	for _, existente := range repositorio.banco.usuarios {
+		if existente.ID != usuarioId && strings.EqualFold(existente.Nick, usuario.Nick) {
+			return repositorios.ErroDeDuplicidade{Campo: "nick"}
+		}
+	}
+
+	for _, existente := range repositorio.banco.usuarios {
+		if existente.ID != usuarioId && strings.EqualFold(existente.Email, usuario.Email) {
+			return repositorios.ErroDeDuplicidade{Campo: "email"}
+		}
+	}
+
+	return nil
+}
+
+// buscarRelacionados lista os usuários de um lado de uma tabela de relacionamento (seguidores, bloqueios
+// ou silenciados), paginados pela data do relacionamento
+func (repositorio Usuarios) buscarRelacionados(
+	relacoes map[relacao]time.Time,
+	paginacao modelos.Paginacao,
+	selecionar func(chave relacao) (uint64, bool),
+) ([]modelos.Usuario, *modelos.Cursor, error) {
+	type relacionado struct {
+		usuario modelos.Usuario
+		desde   time.Time
+	}
+
+	relacionados := make([]relacionado, 0)
+	for chave, desde := range relacoes {
+		if id, ok := selecionar(chave); ok {
+			usuario := repositorio.banco.usuarios[id]
+			relacionados = append(relacionados, relacionado{
+				usuario: modelos.Usuario{ID: usuario.ID, Nome: usuario.Nome, Nick: usuario.Nick, Email: usuario.Email},
+				desde:   desde,
+			})
+		}
+	}
+
+	relacionados, proximoCursor := paginar(relacionados, func(item relacionado) (time.Time, uint64) {
+		return item.desde, item.usuario.ID
+	}, false, paginacao)
+
+	usuarios := make([]modelos.Usuario, 0, len(relacionados))
+	for _, item := range relacionados {
+		usuarios = append(usuarios, item.usuario)
+	}
+
+	return usuarios, proximoCursor, nil
+}
+
+// semSenha retorna somente as colunas lidas pelas consultas públicas do MySQL (sem senha, papel e suspensão)
+func semSenha(usuario modelos.Usuario) modelos.Usuario {
+	return modelos.Usuario{
+		ID:       usuario.ID,
+		Nome:     usuario.Nome,
+		Nick:     usuario.Nick,
+		Email:    usuario.Email,
+		CriadoEm: usuario.CriadoEm,
+		Privado:  usuario.Privado,
+	}
+}
@@ -3,8 +3,10 @@ package repositorios
 import (
 	"api/src/modelos"
 	"database/sql"
+	"time"
 )
 
+// Publicacoes representa o repositório de publicações e curtidas
 type Publicacoes struct {
 	db *sql.DB
 }
@@ -14,18 +16,19 @@ func NovoRepositorioDePublicacoes(db *sql.DB) *Publicacoes {
 	return &Publicacoes{db}
 }
 
-// Criar insere publicação no banco de dados
+// Criar insere publicação no banco de dados, junto com as hashtags e menções extraídas do conteúdo
 func (repositorio Publicacoes) Criar(publicacao modelos.Publicacao) (uint64, error) {
-
-	statement, erro := repositorio.db.Prepare(
-		`insert into publicacoes (titulo, conteudo, autor_id)
-		 values (?, ?, ?)`)
+	transacao, erro := repositorio.db.Begin()
 	if erro != nil {
 		return 0, erro
 	}
-	defer statement.Close()
+	defer transacao.Rollback()
 
-	resultado, erro := statement.Exec(publicacao.Titulo, publicacao.Conteudo, publicacao.AuthorId)
+	resultado, erro := transacao.Exec(
+		`insert into publicacoes (titulo, conteudo, autor_id)
+		 values (?, ?, ?)`,
+		publicacao.Titulo, publicacao.Conteudo, publicacao.AuthorId,
+	)
 	if erro != nil {
 		return 0, erro
 	}
@@ -35,21 +38,41 @@ func (repositorio Publicacoes) Criar(publicacao modelos.Publicacao) (uint64, err
 		return 0, erro
 	}
 
+	if erro = gravarEntidades(transacao, uint64(ultimoIdInserido), publicacao); erro != nil {
+		return 0, erro
+	}
+
+	if erro = transacao.Commit(); erro != nil {
+		return 0, erro
+	}
+
 	return uint64(ultimoIdInserido), nil
 }
 
-// Buscar retorna publicações de acordo com filtros dados.
-func (repositorio Publicacoes) Buscar(usuarioId uint64) ([]modelos.Publicacao, error) {
+// Buscar retorna o feed do usuário (publicações próprias e de quem ele segue), paginado por (criadaEm, id).
+// Publicações ocultadas por denúncias e de usuários silenciados não aparecem no feed.
+// O cursor retornado é nulo quando não há próxima página.
+func (repositorio Publicacoes) Buscar(usuarioId uint64, paginacao modelos.Paginacao) ([]modelos.Publicacao, *modelos.Cursor, error) {
+	cursorData, cursorId := paginacao.Posicao()
 
 	linhas, erro := repositorio.db.Query(
-		`select distinct p.*, u.nick from publicacoes p 
+		`select p.id, p.titulo, p.conteudo, p.autor_id, p.curtidas, p.criadaEm, u.nick,
+		exists(select 1 from curtidas c where c.publicacao_id = p.id and c.usuario_id = ?) as curtido_por_mim,
+		(select count(*) from comentarios co where co.publicacao_id = p.id) as total_comentarios
+		from publicacoes p 
 		inner join usuarios u on u.id = p.autor_id 
-		inner join seguidores s on s.usuario_id = p.autor_id  
-		where u.id = ? or s.seguidor_id = ?
-		order by p.id desc`, usuarioId, usuarioId,
+		where (p.autor_id = ? or p.autor_id in (select s.usuario_id from seguidores s where s.seguidor_id = ?))
+		and p.ocultaEm is null
+		and p.autor_id not in (select si.silenciado_id from silenciados si where si.usuario_id = ?)
+		and (? is null or (p.criadaEm, p.id) < (?, ?))
+		order by p.criadaEm desc, p.id desc
+		limit ?`,
+		usuarioId, usuarioId, usuarioId, usuarioId,
+		cursorData, cursorData, cursorId,
+		paginacao.LimiteDaConsulta(),
 	)
 	if erro != nil {
-		return nil, erro
+		return nil, nil, erro
 	}
 	defer linhas.Close()
 
@@ -66,20 +89,33 @@ func (repositorio Publicacoes) Buscar(usuarioId uint64) ([]modelos.Publicacao, e
 			&publicacao.Curtidas,
 			&publicacao.CriadaEm,
 			&publicacao.AuthorNick,
+			&publicacao.CurtidoPorMim,
+			&publicacao.TotalComentarios,
 		); erro != nil {
-			return nil, erro
+			return nil, nil, erro
 		}
 
 		publicacoes = append(publicacoes, publicacao)
 	}
 
-	return publicacoes, nil
+	var proximoCursor *modelos.Cursor
+	if paginacao.TemProximaPagina(len(publicacoes)) {
+		publicacoes = publicacoes[:paginacao.Limite]
+		ultima := publicacoes[len(publicacoes)-1]
+		proximoCursor = &modelos.Cursor{CriadoEm: ultima.CriadaEm, ID: ultima.ID}
+	}
+
+	if erro = repositorio.montarEntidades(publicacoes); erro != nil {
+		return nil, nil, erro
+	}
+
+	return publicacoes, proximoCursor, nil
 }
 
 // BuscarPorId retorna dados de uma publicação dado seu ID
 func (repositorio Publicacoes) BuscarPorId(publicacaoId uint64) (modelos.Publicacao, error) {
 	linhas, erro := repositorio.db.Query(
-		`select p.*, u.nick from publicacoes p
+		`select p.id, p.titulo, p.conteudo, p.autor_id, p.curtidas, p.criadaEm, u.nick, p.ocultaEm from publicacoes p
 		inner join usuarios u on u.id = p.autor_id
 		where p.id = ?`, publicacaoId,
 	)
@@ -99,28 +135,43 @@ func (repositorio Publicacoes) BuscarPorId(publicacaoId uint64) (modelos.Publica
 			&publicacao.Curtidas,
 			&publicacao.CriadaEm,
 			&publicacao.AuthorNick,
+			&publicacao.OcultaEm,
 		); erro != nil {
 			return modelos.Publicacao{}, erro
 		}
 	}
 
+	if publicacao.ID != 0 {
+		mencoes, erro := repositorio.buscarMencoes([]uint64{publicacao.ID})
+		if erro != nil {
+			return modelos.Publicacao{}, erro
+		}
+		publicacao.MontarEntidades(mencoes[publicacao.ID])
+	}
+
 	return publicacao, nil
 }
 
-// Atualizar dados de um usuário no banco de dados
+// Atualizar altera título e conteúdo da publicação e regrava suas hashtags e menções
 func (repositorio Publicacoes) Atualizar(publicacaoId uint64, publicacao modelos.Publicacao) error {
-
-	statement, erro := repositorio.db.Prepare(`update publicacoes set titulo=?, conteudo=? where id=?`)
+	transacao, erro := repositorio.db.Begin()
 	if erro != nil {
 		return erro
 	}
-	defer statement.Close()
+	defer transacao.Rollback()
 
-	if _, erro := statement.Exec(publicacao.Titulo, publicacao.Conteudo, publicacaoId); erro != nil {
+	if _, erro = transacao.Exec(
+		`update publicacoes set titulo=?, conteudo=? where id=?`,
+		publicacao.Titulo, publicacao.Conteudo, publicacaoId,
+	); erro != nil {
 		return erro
 	}
 
-	return nil
+	if erro = gravarEntidades(transacao, publicacaoId, publicacao); erro != nil {
+		return erro
+	}
+
+	return transacao.Commit()
 }
 
 // RemoverUsuario remove usuário da tabela
@@ -138,15 +189,29 @@ func (repositorio Publicacoes) RemoverPublicacao(publicacaoId uint64) error {
 	return nil
 }
 
-// BuscarPorUsuario busca todas as publicações de um usuário específico
-func (repositorio Publicacoes) BuscarPorUsuario(usuarioId uint64) ([]modelos.Publicacao, error) {
+// BuscarPorUsuario busca as publicações de um usuário específico, paginadas por (criadaEm, id).
+// usuarioLogadoId é utilizado para indicar se as publicações foram curtidas por quem está consultando.
+// Publicações ocultadas pela moderação só aparecem para o próprio autor.
+func (repositorio Publicacoes) BuscarPorUsuario(usuarioId, usuarioLogadoId uint64, paginacao modelos.Paginacao) ([]modelos.Publicacao, *modelos.Cursor, error) {
+	cursorData, cursorId := paginacao.Posicao()
+
 	linhas, erro := repositorio.db.Query(
-		`select p.*, u.nick from publicacoes p 
+		`select p.id, p.titulo, p.conteudo, p.autor_id, p.curtidas, p.criadaEm, u.nick,
+		exists(select 1 from curtidas c where c.publicacao_id = p.id and c.usuario_id = ?) as curtido_por_mim,
+		(select count(*) from comentarios co where co.publicacao_id = p.id) as total_comentarios
+		from publicacoes p 
 		inner join usuarios u on u.id = p.autor_id 
-		where u.id = ? order by p.id desc`, usuarioId, 
+		where u.id = ?
+		and (p.ocultaEm is null or p.autor_id = ?)
+		and (? is null or (p.criadaEm, p.id) < (?, ?))
+		order by p.criadaEm desc, p.id desc
+		limit ?`,
+		usuarioLogadoId, usuarioId, usuarioLogadoId,
+		cursorData, cursorData, cursorId,
+		paginacao.LimiteDaConsulta(),
 	)
 	if erro != nil {
-		return nil, erro
+		return nil, nil, erro
 	}
 	defer linhas.Close()
 
@@ -163,48 +228,264 @@ func (repositorio Publicacoes) BuscarPorUsuario(usuarioId uint64) ([]modelos.Pub
 			&publicacao.Curtidas,
 			&publicacao.CriadaEm,
 			&publicacao.AuthorNick,
+			&publicacao.CurtidoPorMim,
+			&publicacao.TotalComentarios,
 		); erro != nil {
-			return nil, erro
+			return nil, nil, erro
 		}
 
 		publicacoes = append(publicacoes, publicacao)
 	}
 
-	return publicacoes, nil
+	var proximoCursor *modelos.Cursor
+	if paginacao.TemProximaPagina(len(publicacoes)) {
+		publicacoes = publicacoes[:paginacao.Limite]
+		ultima := publicacoes[len(publicacoes)-1]
+		proximoCursor = &modelos.Cursor{CriadoEm: ultima.CriadaEm, ID: ultima.ID}
+	}
+
+	if erro = repositorio.montarEntidades(publicacoes); erro != nil {
+		return nil, nil, erro
+	}
+
+	return publicacoes, proximoCursor, nil
+}
+
+// Pesquisar faz a busca textual nas publicações visíveis ao usuário logado, ordenadas pela relevância
+// e paginadas por (relevancia, criadaEm, id). Ficam de fora as publicações ocultadas, as de usuários
+// com bloqueio em qualquer direção e as de contas privadas que o usuário logado não segue.
+// A relevância é arredondada para que o valor guardado no cursor seja comparado sem perda de precisão.
+func (repositorio Publicacoes) Pesquisar(busca string, usuarioLogadoId uint64, paginacao modelos.Paginacao) ([]modelos.PublicacaoEncontrada, *modelos.Cursor, error) {
+	cursorRelevancia, cursorData, cursorId := paginacao.PosicaoPorRelevancia()
+
+	linhas, erro := repositorio.db.Query(
+		`select p.id, p.titulo, p.conteudo, p.autor_id, p.curtidas, p.criadaEm, u.nick,
+		exists(select 1 from curtidas c where c.publicacao_id = p.id and c.usuario_id = ?) as curtido_por_mim,
+		(select count(*) from comentarios co where co.publicacao_id = p.id) as total_comentarios,
+		round(match(p.titulo, p.conteudo) against (? in natural language mode), 6) as relevancia
+		from publicacoes p
+		inner join usuarios u on u.id = p.autor_id
+		where match(p.titulo, p.conteudo) against (? in natural language mode)
+		and p.ocultaEm is null
+		and not exists(
+			select 1 from bloqueios b
+			where (b.usuario_id = ? and b.bloqueado_id = p.autor_id)
+			or (b.usuario_id = p.autor_id and b.bloqueado_id = ?)
+		)
+		and (u.privado = false or p.autor_id = ?
+			or exists(select 1 from seguidores s where s.usuario_id = p.autor_id and s.seguidor_id = ?))
+		and (? is null or (round(match(p.titulo, p.conteudo) against (? in natural language mode), 6), p.criadaEm, p.id) < (?, ?, ?))
+		order by relevancia desc, p.criadaEm desc, p.id desc
+		limit ?`,
+		usuarioLogadoId, busca, busca,
+		usuarioLogadoId, usuarioLogadoId,
+		usuarioLogadoId, usuarioLogadoId,
+		cursorRelevancia, busca, cursorRelevancia, cursorData, cursorId,
+		paginacao.LimiteDaConsulta(),
+	)
+	if erro != nil {
+		return nil, nil, erro
+	}
+	defer linhas.Close()
+
+	publicacoes := make([]modelos.PublicacaoEncontrada, 0)
+
+	for linhas.Next() {
+		var publicacao modelos.PublicacaoEncontrada
+
+		if erro = linhas.Scan(
+			&publicacao.ID,
+			&publicacao.Titulo,
+			&publicacao.Conteudo,
+			&publicacao.AuthorId,
+			&publicacao.Curtidas,
+			&publicacao.CriadaEm,
+			&publicacao.AuthorNick,
+			&publicacao.CurtidoPorMim,
+			&publicacao.TotalComentarios,
+			&publicacao.Relevancia,
+		); erro != nil {
+			return nil, nil, erro
+		}
+
+		publicacoes = append(publicacoes, publicacao)
+	}
+
+	var proximoCursor *modelos.Cursor
+	if paginacao.TemProximaPagina(len(publicacoes)) {
+		publicacoes = publicacoes[:paginacao.Limite]
+		ultima := publicacoes[len(publicacoes)-1]
+		proximoCursor = &modelos.Cursor{Relevancia: ultima.Relevancia, CriadoEm: ultima.CriadaEm, ID: ultima.ID}
+	}
+
+	ids := make([]uint64, 0, len(publicacoes))
+	for _, publicacao := range publicacoes {
+		ids = append(ids, publicacao.ID)
+	}
+
+	mencoes, erro := repositorio.buscarMencoes(ids)
+	if erro != nil {
+		return nil, nil, erro
+	}
+
+	for i := range publicacoes {
+		publicacoes[i].MontarEntidades(mencoes[publicacoes[i].ID])
+	}
+
+	return publicacoes, proximoCursor, nil
 }
 
-// Curtir incrementa a curtida em um
-func (repositorio Publicacoes) Curtir(publicacaoId uint64) error {
-	statement, erro := repositorio.db.Prepare(`update publicacoes set curtidas=curtidas + 1 where id=?`)
+// Curtir registra a curtida do usuário na publicação, ignorando curtidas repetidas
+func (repositorio Publicacoes) Curtir(usuarioId, publicacaoId uint64) error {
+	transacao, erro := repositorio.db.Begin()
+	if erro != nil {
+		return erro
+	}
+	defer transacao.Rollback()
+
+	resultado, erro := transacao.Exec(
+		"insert ignore into curtidas (usuario_id, publicacao_id) values (?, ?)", // ignore: curtida já existente não gera erro.
+		usuarioId, publicacaoId,
+	)
+	if erro != nil {
+		return erro
+	}
+
+	linhasAfetadas, erro := resultado.RowsAffected()
+	if erro != nil {
+		return erro
+	}
+
+	// O contador só é incrementado quando a curtida é nova.
+	if linhasAfetadas > 0 {
+		if _, erro = transacao.Exec(
+			"update publicacoes set curtidas = curtidas + 1 where id = ?", publicacaoId,
+		); erro != nil {
+			return erro
+		}
+	}
+
+	return transacao.Commit()
+}
+
+// Descurtir remove a curtida do usuário na publicação, caso exista
+func (repositorio Publicacoes) Descurtir(usuarioId, publicacaoId uint64) error {
+	transacao, erro := repositorio.db.Begin()
+	if erro != nil {
+		return erro
+	}
+	defer transacao.Rollback()
+
+	resultado, erro := transacao.Exec(
+		"delete from curtidas where usuario_id = ? and publicacao_id = ?",
+		usuarioId, publicacaoId,
+	)
+	if erro != nil {
+		return erro
+	}
+
+	linhasAfetadas, erro := resultado.RowsAffected()
+	if erro != nil {
+		return erro
+	}
+
+	// O contador só é decrementado quando havia curtida do usuário.
+	if linhasAfetadas > 0 {
+		if _, erro = transacao.Exec(
+			`update publicacoes set curtidas=
+				case when curtidas > 0
+					then curtidas - 1
+					else curtidas end
+				where id = ?`, publicacaoId,
+		); erro != nil {
+			return erro
+		}
+	}
+
+	return transacao.Commit()
+}
+
+// BuscarCurtidas retorna os usuários que curtiram uma publicação, das curtidas mais recentes para as mais antigas.
+// A paginação usa a data da curtida e o id do usuário.
+func (repositorio Publicacoes) BuscarCurtidas(publicacaoId uint64, paginacao modelos.Paginacao) ([]modelos.Usuario, *modelos.Cursor, error) {
+	cursorData, cursorId := paginacao.Posicao()
+
+	linhas, erro := repositorio.db.Query(
+		`select u.id, u.nome, u.nick, c.criadaEm from curtidas c
+		inner join usuarios u on u.id = c.usuario_id
+		where c.publicacao_id = ?
+		and (? is null or (c.criadaEm, c.usuario_id) < (?, ?))
+		order by c.criadaEm desc, c.usuario_id desc
+		limit ?`,
+		publicacaoId,
+		cursorData, cursorData, cursorId,
+		paginacao.LimiteDaConsulta(),
+	)
+	if erro != nil {
+		return nil, nil, erro
+	}
+	defer linhas.Close()
+
+	usuarios := make([]modelos.Usuario, 0)
+	datasDasCurtidas := make([]time.Time, 0)
+
+	for linhas.Next() {
+		var usuario modelos.Usuario
+		var curtidaEm time.Time
+
+		if erro = linhas.Scan(
+			&usuario.ID,
+			&usuario.Nome,
+			&usuario.Nick,
+			&curtidaEm,
+		); erro != nil {
+			return nil, nil, erro
+		}
+
+		usuarios = append(usuarios, usuario)
+		datasDasCurtidas = append(datasDasCurtidas, curtidaEm)
+	}
+
+	var proximoCursor *modelos.Cursor
+	if paginacao.TemProximaPagina(len(usuarios)) {
+		usuarios = usuarios[:paginacao.Limite]
+		ultimo := len(usuarios) - 1
+		proximoCursor = &modelos.Cursor{CriadoEm: datasDasCurtidas[ultimo], ID: usuarios[ultimo].ID}
+	}
+
+	return usuarios, proximoCursor, nil
+}
+
+// Ocultar retira a publicação dos feeds, mantendo a data da primeira ocultação
+func (repositorio Publicacoes) Ocultar(publicacaoId uint64) error {
+	statement, erro := repositorio.db.Prepare(
+		"update publicacoes set ocultaEm = current_timestamp where id = ? and ocultaEm is null",
+	)
 	if erro != nil {
 		return erro
 	}
 	defer statement.Close()
 
-	if _, erro := statement.Exec(publicacaoId); erro != nil {
+	if _, erro = statement.Exec(publicacaoId); erro != nil {
 		return erro
 	}
 
 	return nil
 }
 
-// Descurtir incrementa a curtida em um
-func (repositorio Publicacoes) Descurtir(publicacaoId uint64) error {
+// Reexibir devolve aos feeds uma publicação ocultada
+func (repositorio Publicacoes) Reexibir(publicacaoId uint64) error {
 	statement, erro := repositorio.db.Prepare(
-		`update publicacoes set curtidas=
-			case when curtidas > 0 
-				then curtidas - 1
-				else curtidas end
-			where id=?`,
+		"update publicacoes set ocultaEm = null where id = ?",
 	)
 	if erro != nil {
 		return erro
 	}
 	defer statement.Close()
 
-	if _, erro := statement.Exec(publicacaoId); erro != nil {
+	if _, erro = statement.Exec(publicacaoId); erro != nil {
 		return erro
 	}
 
 	return nil
-}
\ No newline at end of file
+}
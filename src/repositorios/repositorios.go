@@ -0,0 +1,123 @@
+package repositorios
+
+import (
+	"api/src/modelos"
+	"context"
+	"database/sql"
+	"time"
+)
+
+// RepositorioDeUsuarios define as operações de persistência de usuários, seguidores, pedidos para seguir,
+// bloqueios e silenciamentos
+type RepositorioDeUsuarios interface {
+	Criar(usuario modelos.Usuario) (uint64, error)
+	Buscar(nomeOuNick string, usuarioLogadoId uint64, paginacao modelos.Paginacao) ([]modelos.Usuario, *modelos.Cursor, error)
+	BuscarPorId(usuarioId uint64) (modelos.Usuario, error)
+	Atualizar(usuarioId uint64, usuario modelos.Usuario) error
+	RemoverUsuario(usuarioId uint64) error
+	NickEmUso(nick string) (bool, error)
+	EmailEmUso(email string) (bool, error)
+	BuscarPorEmail(email string) (modelos.Usuario, error)
+	Seguir(usuarioId, seguidorId uint64) error
+	PararDeSeguir(usuarioId, seguidorId uint64) error
+	BuscarSeguidores(usuarioId uint64, paginacao modelos.Paginacao) ([]modelos.Usuario, *modelos.Cursor, error)
+	BuscarSeguindo(usuarioId uint64, paginacao modelos.Paginacao) ([]modelos.Usuario, *modelos.Cursor, error)
+	BuscarSenha(usuarioId uint64) (string, error)
+	AtualizarSenha(usuarioId uint64, senhaComHash string) error
+	BuscarSituacao(usuarioId uint64) (modelos.Usuario, error)
+	BuscarContas(paginacao modelos.Paginacao) ([]modelos.Usuario, *modelos.Cursor, error)
+	Suspender(usuarioId uint64) error
+	Reativar(usuarioId uint64) error
+	Bloquear(usuarioId, bloqueadoId uint64) error
+	Desbloquear(usuarioId, bloqueadoId uint64) error
+	Bloqueado(usuarioId, outroUsuarioId uint64) (bool, error)
+	BuscarBloqueados(usuarioId uint64, paginacao modelos.Paginacao) ([]modelos.Usuario, *modelos.Cursor, error)
+	Silenciar(usuarioId, silenciadoId uint64) error
+	Dessilenciar(usuarioId, silenciadoId uint64) error
+	BuscarSilenciados(usuarioId uint64, paginacao modelos.Paginacao) ([]modelos.Usuario, *modelos.Cursor, error)
+	Segue(usuarioId, seguidorId uint64) (bool, error)
+	SolicitarSeguir(usuarioId, solicitanteId uint64) error
+	BuscarSolicitacoes(usuarioId uint64, paginacao modelos.Paginacao) ([]modelos.Usuario, *modelos.Cursor, error)
+	AceitarSolicitacao(usuarioId, solicitanteId uint64) (bool, error)
+	RemoverSolicitacao(usuarioId, solicitanteId uint64) (bool, error)
+	VerificarEmail(usuarioId uint64, email string) (bool, error)
+}
+
+// RepositorioDePublicacoes define as operações de persistência de publicações e curtidas
+type RepositorioDePublicacoes interface {
+	Criar(publicacao modelos.Publicacao) (uint64, error)
+	Buscar(usuarioId uint64, paginacao modelos.Paginacao) ([]modelos.Publicacao, *modelos.Cursor, error)
+	BuscarPorId(publicacaoId uint64) (modelos.Publicacao, error)
+	Atualizar(publicacaoId uint64, publicacao modelos.Publicacao) error
+	RemoverPublicacao(publicacaoId uint64) error
+	BuscarPorUsuario(usuarioId, usuarioLogadoId uint64, paginacao modelos.Paginacao) ([]modelos.Publicacao, *modelos.Cursor, error)
+	Pesquisar(busca string, usuarioLogadoId uint64, paginacao modelos.Paginacao) ([]modelos.PublicacaoEncontrada, *modelos.Cursor, error)
+	BuscarPorHashtag(hashtag string, usuarioLogadoId uint64, paginacao modelos.Paginacao) ([]modelos.Publicacao, *modelos.Cursor, error)
+	BuscarHashtagsEmAlta(desde time.Time, limite uint64) ([]modelos.HashtagEmAlta, error)
+	Curtir(usuarioId, publicacaoId uint64) error
+	Descurtir(usuarioId, publicacaoId uint64) error
+	BuscarCurtidas(publicacaoId uint64, paginacao modelos.Paginacao) ([]modelos.Usuario, *modelos.Cursor, error)
+	Ocultar(publicacaoId uint64) error
+	Reexibir(publicacaoId uint64) error
+}
+
+// RepositorioDeComentarios define as operações de persistência de comentários
+type RepositorioDeComentarios interface {
+	Criar(comentario modelos.Comentario) (uint64, error)
+	BuscarPorPublicacao(publicacaoId uint64, paginacao modelos.Paginacao) ([]modelos.Comentario, *modelos.Cursor, error)
+	BuscarPorId(comentarioId uint64) (modelos.Comentario, error)
+	Atualizar(comentarioId uint64, comentario modelos.Comentario) error
+	Remover(comentarioId uint64) error
+}
+
+// RepositorioDeTokens define as operações de persistência de refresh tokens, tokens de acesso revogados
+// e tokens de redefinição de senha
+type RepositorioDeTokens interface {
+	CriarRefreshToken(refreshToken modelos.RefreshToken) error
+	BuscarRefreshToken(tokenHash string) (modelos.RefreshToken, error)
+	RevogarRefreshToken(refreshTokenId uint64) (bool, error)
+	RevogarAcesso(jti string, expiraEm time.Time) error
+	RevogarSessoesDoUsuario(usuarioId uint64, acessoExpiraEm time.Time) error
+	AcessoRevogado(jti string) (bool, error)
+	CriarTokenDeRedefinicao(token modelos.TokenDeRedefinicao) error
+	BuscarTokenDeRedefinicao(tokenHash string) (modelos.TokenDeRedefinicao, error)
+	RedefinirSenha(token modelos.TokenDeRedefinicao, senhaComHash string, acessoExpiraEm time.Time) (bool, error)
+}
+
+// RepositorioDeDenuncias define as operações de persistência de denúncias e da fila de moderação
+type RepositorioDeDenuncias interface {
+	Criar(denuncia modelos.Denuncia) (uint64, error)
+	ContarDenunciantes(publicacaoId uint64) (uint64, error)
+	BuscarPorSituacao(situacao string, paginacao modelos.Paginacao) ([]modelos.Denuncia, *modelos.Cursor, error)
+	BuscarPorId(denunciaId uint64) (modelos.Denuncia, error)
+	Moderar(denunciaId, moderadorId uint64, situacao string) (bool, error)
+}
+
+// RepositorioDeSaude define as verificações de disponibilidade do banco usadas pela prontidão da API
+type RepositorioDeSaude interface {
+	Pingar(ctx context.Context) error
+	MigracoesPendentes(ctx context.Context) (int, error)
+}
+
+// Repositorios reúne as implementações de persistência utilizadas pela API.
+// Em produção são usados os repositórios MySQL (NovosRepositorios); em testes, os do pacote memoria.
+type Repositorios struct {
+	Usuarios    RepositorioDeUsuarios
+	Publicacoes RepositorioDePublicacoes
+	Comentarios RepositorioDeComentarios
+	Tokens      RepositorioDeTokens
+	Denuncias   RepositorioDeDenuncias
+	Saude       RepositorioDeSaude
+}
+
+// NovosRepositorios cria os repositórios MySQL, todos compartilhando o pool de conexões informado
+func NovosRepositorios(db *sql.DB) Repositorios {
+	return Repositorios{
+		Usuarios:    NovoRepositorioDeUsuarios(db),
+		Publicacoes: NovoRepositorioDePublicacoes(db),
+		Comentarios: NovoRepositorioDeComentarios(db),
+		Tokens:      NovoRepositorioDeTokens(db),
+		Denuncias:   NovoRepositorioDeDenuncias(db),
+		Saude:       NovoRepositorioDeSaude(db),
+	}
+}
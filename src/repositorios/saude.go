@@ -0,0 +1,27 @@
+package repositorios
+
+import (
+	"api/src/migracoes"
+	"context"
+	"database/sql"
+)
+
+// Saude representa as verificações de disponibilidade do banco de dados
+type Saude struct {
+	db *sql.DB
+}
+
+// NovoRepositorioDeSaude cria um repositório de saúde a partir do pool de conexões
+func NovoRepositorioDeSaude(db *sql.DB) *Saude {
+	return &Saude{db}
+}
+
+// Pingar verifica se o MySQL responde, usando o mesmo pool das requisições
+func (repositorio Saude) Pingar(ctx context.Context) error {
+	return repositorio.db.PingContext(ctx)
+}
+
+// MigracoesPendentes retorna a quantidade de migrações ainda não aplicadas ao banco, sem alterá-lo
+func (repositorio Saude) MigracoesPendentes(ctx context.Context) (int, error) {
+	return migracoes.ContarPendentes(ctx, repositorio.db)
+}
@@ -0,0 +1,106 @@
+package repositorios
+
+import "api/src/modelos"
+
+// Segue indica se o seguidor segue o usuário
+func (repositorio Usuarios) Segue(usuarioId, seguidorId uint64) (bool, error) {
+	var segue bool
+
+	if erro := repositorio.db.QueryRow(
+		"select exists(select 1 from seguidores where usuario_id = ? and seguidor_id = ?)",
+		usuarioId, seguidorId,
+	).Scan(&segue); erro != nil {
+		return false, erro
+	}
+
+	return segue, nil
+}
+
+// SolicitarSeguir registra o pedido do solicitante para seguir a conta privada do usuário, ignorando pedidos repetidos
+func (repositorio Usuarios) SolicitarSeguir(usuarioId, solicitanteId uint64) error {
+	statement, erro := repositorio.db.Prepare(
+		"insert ignore into solicitacoes_seguir (usuario_id, solicitante_id) values (?, ?)",
+	)
+	if erro != nil {
+		return erro
+	}
+	defer statement.Close()
+
+	if _, erro := statement.Exec(usuarioId, solicitanteId); erro != nil {
+		return erro
+	}
+
+	return nil
+}
+
+// BuscarSolicitacoes retorna os pedidos para seguir recebidos pelo usuário, dos mais recentes para os mais antigos
+func (repositorio Usuarios) BuscarSolicitacoes(usuarioId uint64, paginacao modelos.Paginacao) ([]modelos.Usuario, *modelos.Cursor, error) {
+	return repositorio.buscarRestritos(
+		`SELECT u.id, u.nome, u.nick, u.email, so.criadaEm from solicitacoes_seguir so
+		join usuarios u on u.id = so.solicitante_id
+		WHERE so.usuario_id = ?
+		and (? is null or (so.criadaEm, u.id) < (?, ?))
+		order by so.criadaEm desc, u.id desc
+		limit ?`,
+		usuarioId, paginacao,
+	)
+}
+
+// AceitarSolicitacao transforma o pedido pendente em seguidor. Retorna false se não havia pedido.
+func (repositorio Usuarios) AceitarSolicitacao(usuarioId, solicitanteId uint64) (bool, error) {
+	transacao, erro := repositorio.db.Begin()
+	if erro != nil {
+		return false, erro
+	}
+	defer transacao.Rollback()
+
+	resultado, erro := transacao.Exec(
+		"delete from solicitacoes_seguir where usuario_id = ? and solicitante_id = ?",
+		usuarioId, solicitanteId,
+	)
+	if erro != nil {
+		return false, erro
+	}
+
+	linhasAfetadas, erro := resultado.RowsAffected()
+	if erro != nil {
+		return false, erro
+	}
+
+	if linhasAfetadas == 0 {
+		return false, nil
+	}
+
+	if _, erro = transacao.Exec(
+		"insert ignore into seguidores (usuario_id, seguidor_id) values (?, ?)",
+		usuarioId, solicitanteId,
+	); erro != nil {
+		return false, erro
+	}
+
+	return true, transacao.Commit()
+}
+
+// RemoverSolicitacao apaga o pedido pendente, seja recusado pelo usuário ou cancelado pelo solicitante.
+// Retorna false se não havia pedido.
+func (repositorio Usuarios) RemoverSolicitacao(usuarioId, solicitanteId uint64) (bool, error) {
+	statement, erro := repositorio.db.Prepare(
+		"delete from solicitacoes_seguir where usuario_id = ? and solicitante_id = ?",
+	)
+	if erro != nil {
+		return false, erro
+	}
+	defer statement.Close()
+
+	resultado, erro := statement.Exec(usuarioId, solicitanteId)
+	if erro != nil {
+		return false, erro
+	}
+
+	linhasAfetadas, erro := resultado.RowsAffected()
+	if erro != nil {
+		return false, erro
+	}
+
+	return linhasAfetadas > 0, nil
+}
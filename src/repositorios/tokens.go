@@ -0,0 +1,256 @@
+package repositorios
+
+import (
+	"api/src/modelos"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// Tokens representa o repositório de refresh tokens, tokens de acesso revogados e tokens de redefinição de senha
+type Tokens struct {
+	db *sql.DB
+}
+
+// NovoRepositorioDeTokens cria um repositório de refresh tokens, tokens de acesso revogados e tokens de redefinição de senha
+func NovoRepositorioDeTokens(db *sql.DB) *Tokens {
+	return &Tokens{db}
+}
+
+// CriarRefreshToken persiste o hash de um refresh token emitido para o usuário
+func (repositorio Tokens) CriarRefreshToken(refreshToken modelos.RefreshToken) error {
+	statement, erro := repositorio.db.Prepare(
+		`insert into refresh_tokens (usuario_id, token_hash, acesso_jti, expiraEm)
+		 values (?, ?, ?, ?)`)
+	if erro != nil {
+		return erro
+	}
+	defer statement.Close()
+
+	if _, erro = statement.Exec(
+		refreshToken.UsuarioId,
+		refreshToken.TokenHash,
+		refreshToken.AcessoJti,
+		refreshToken.ExpiraEm,
+	); erro != nil {
+		return erro
+	}
+
+	return nil
+}
+
+// BuscarRefreshToken retorna um refresh token dado o hash do seu valor
+func (repositorio Tokens) BuscarRefreshToken(tokenHash string) (modelos.RefreshToken, error) {
+	linha, erro := repositorio.db.Query(
+		`select id, usuario_id, token_hash, acesso_jti, expiraEm, revogadoEm
+		from refresh_tokens where token_hash = ?`, tokenHash,
+	)
+	if erro != nil {
+		return modelos.RefreshToken{}, erro
+	}
+	defer linha.Close()
+
+	var refreshToken modelos.RefreshToken
+	var revogadoEm sql.NullTime
+
+	if linha.Next() {
+		if erro = linha.Scan(
+			&refreshToken.ID,
+			&refreshToken.UsuarioId,
+			&refreshToken.TokenHash,
+			&refreshToken.AcessoJti,
+			&refreshToken.ExpiraEm,
+			&revogadoEm,
+		); erro != nil {
+			return modelos.RefreshToken{}, erro
+		}
+	}
+
+	if revogadoEm.Valid {
+		refreshToken.RevogadoEm = &revogadoEm.Time
+	}
+
+	return refreshToken, nil
+}
+
+// RevogarRefreshToken marca o refresh token como revogado.
+// Retorna false quando o token já havia sido revogado, o que permite detectar uso concorrente na renovação.
+func (repositorio Tokens) RevogarRefreshToken(refreshTokenId uint64) (bool, error) {
+	statement, erro := repositorio.db.Prepare(
+		"update refresh_tokens set revogadoEm = current_timestamp where id = ? and revogadoEm is null",
+	)
+	if erro != nil {
+		return false, erro
+	}
+	defer statement.Close()
+
+	resultado, erro := statement.Exec(refreshTokenId)
+	if erro != nil {
+		return false, erro
+	}
+
+	linhasAfetadas, erro := resultado.RowsAffected()
+	if erro != nil {
+		return false, erro
+	}
+
+	return linhasAfetadas > 0, nil
+}
+
+// RevogarAcesso inclui o token de acesso (jti) na lista de tokens revogados até sua expiração
+func (repositorio Tokens) RevogarAcesso(jti string, expiraEm time.Time) error {
+	statement, erro := repositorio.db.Prepare(
+		"insert ignore into tokens_revogados (jti, expiraEm) values (?, ?)",
+	)
+	if erro != nil {
+		return erro
+	}
+	defer statement.Close()
+
+	if _, erro = statement.Exec(jti, expiraEm); erro != nil {
+		return erro
+	}
+
+	return nil
+}
+
+// RevogarSessoesDoUsuario revoga todos os refresh tokens ativos do usuário
+// e inclui na lista de revogação os tokens de acesso emitidos junto com eles.
+// Os tokens de acesso dos refresh tokens já revogados entraram na lista quando eles foram revogados (renovação e logout).
+func (repositorio Tokens) RevogarSessoesDoUsuario(usuarioId uint64, acessoExpiraEm time.Time) error {
+	transacao, erro := repositorio.db.Begin()
+	if erro != nil {
+		return erro
+	}
+	defer transacao.Rollback()
+
+	if erro = revogarSessoes(transacao, usuarioId, acessoExpiraEm); erro != nil {
+		return erro
+	}
+
+	return transacao.Commit()
+}
+
+// revogarSessoes revoga, na transação informada, os refresh tokens ativos do usuário e os tokens de acesso emitidos com eles
+func revogarSessoes(transacao *sql.Tx, usuarioId uint64, acessoExpiraEm time.Time) error {
+	if _, erro := transacao.Exec(
+		`insert ignore into tokens_revogados (jti, expiraEm)
+		select acesso_jti, ? from refresh_tokens
+		where usuario_id = ? and revogadoEm is null`,
+		acessoExpiraEm, usuarioId,
+	); erro != nil {
+		return erro
+	}
+
+	if _, erro := transacao.Exec(
+		"update refresh_tokens set revogadoEm = current_timestamp where usuario_id = ? and revogadoEm is null",
+		usuarioId,
+	); erro != nil {
+		return erro
+	}
+
+	return nil
+}
+
+// AcessoRevogado indica se o token de acesso (jti) está na lista de tokens revogados
+func (repositorio Tokens) AcessoRevogado(jti string) (bool, error) {
+	var revogado bool
+
+	if erro := repositorio.db.QueryRow(
+		"select exists(select 1 from tokens_revogados where jti = ? and expiraEm > current_timestamp)", jti,
+	).Scan(&revogado); erro != nil {
+		return false, erro
+	}
+
+	return revogado, nil
+}
+
+// CriarTokenDeRedefinicao persiste o hash de um token de redefinição de senha emitido para o usuário
+func (repositorio Tokens) CriarTokenDeRedefinicao(token modelos.TokenDeRedefinicao) error {
+	statement, erro := repositorio.db.Prepare(
+		"insert into tokens_redefinicao_senha (usuario_id, token_hash, expiraEm) values (?, ?, ?)",
+	)
+	if erro != nil {
+		return erro
+	}
+	defer statement.Close()
+
+	if _, erro = statement.Exec(token.UsuarioId, token.TokenHash, token.ExpiraEm); erro != nil {
+		return erro
+	}
+
+	return nil
+}
+
+// BuscarTokenDeRedefinicao retorna um token de redefinição de senha dado o hash do seu valor.
+// Token inexistente resulta em ID zero.
+func (repositorio Tokens) BuscarTokenDeRedefinicao(tokenHash string) (modelos.TokenDeRedefinicao, error) {
+	var token modelos.TokenDeRedefinicao
+	var usadoEm sql.NullTime
+
+	erro := repositorio.db.QueryRow(
+		"select id, usuario_id, token_hash, expiraEm, usadoEm from tokens_redefinicao_senha where token_hash = ?",
+		tokenHash,
+	).Scan(&token.ID, &token.UsuarioId, &token.TokenHash, &token.ExpiraEm, &usadoEm)
+	if errors.Is(erro, sql.ErrNoRows) {
+		return modelos.TokenDeRedefinicao{}, nil
+	}
+	if erro != nil {
+		return modelos.TokenDeRedefinicao{}, erro
+	}
+
+	if usadoEm.Valid {
+		token.UsadoEm = &usadoEm.Time
+	}
+
+	return token, nil
+}
+
+// RedefinirSenha grava a nova senha do usuário, marca o token como usado, invalida os demais tokens pendentes
+// do usuário e encerra todas as suas sessões, tudo na mesma transação: se algo falhar, o token continua valendo.
+// Retorna false quando o token já havia sido usado, o que impede duas redefinições concorrentes.
+func (repositorio Tokens) RedefinirSenha(token modelos.TokenDeRedefinicao, senhaComHash string, acessoExpiraEm time.Time) (bool, error) {
+	transacao, erro := repositorio.db.Begin()
+	if erro != nil {
+		return false, erro
+	}
+	defer transacao.Rollback()
+
+	resultado, erro := transacao.Exec(
+		"update tokens_redefinicao_senha set usadoEm = current_timestamp where id = ? and usadoEm is null",
+		token.ID,
+	)
+	if erro != nil {
+		return false, erro
+	}
+
+	linhasAfetadas, erro := resultado.RowsAffected()
+	if erro != nil {
+		return false, erro
+	}
+
+	if linhasAfetadas == 0 {
+		return false, nil
+	}
+
+	if _, erro = transacao.Exec(
+		"update tokens_redefinicao_senha set usadoEm = current_timestamp where usuario_id = ? and usadoEm is null",
+		token.UsuarioId,
+	); erro != nil {
+		return false, erro
+	}
+
+	if _, erro = transacao.Exec("update usuarios set senha = ? where id = ?", senhaComHash, token.UsuarioId); erro != nil {
+		return false, erro
+	}
+
+	if erro = revogarSessoes(transacao, token.UsuarioId, acessoExpiraEm); erro != nil {
+		return false, erro
+	}
+
+	if erro = transacao.Commit(); erro != nil {
+		return false, erro
+	}
+
+	return true, nil
+}
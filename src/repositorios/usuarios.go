@@ -4,8 +4,10 @@ import (
 	"api/src/modelos"
 	"database/sql"
 	"fmt"
+	"time"
 )
 
+// Usuarios representa o repositório de usuários e de suas relações (seguidores, bloqueios e solicitações)
 type Usuarios struct {
 	db *sql.DB
 }
@@ -19,17 +21,17 @@ func NovoRepositorioDeUsuarios(db *sql.DB) *Usuarios {
 func (repositorio Usuarios) Criar(usuario modelos.Usuario) (uint64, error) {
 
 	statement, erro := repositorio.db.Prepare(
-		`insert into usuarios (nome, nick, email, senha)
-		 values (?, ?, ?, ?)`)
+		`insert into usuarios (nome, nick, email, senha, privado)
+		 values (?, ?, ?, ?, ?)`)
 
 	if erro != nil {
 		return 0, erro
 	}
 	defer statement.Close()
 
-	resultado, erro := statement.Exec(usuario.Nome, usuario.Nick, usuario.Email, usuario.Senha)
+	resultado, erro := statement.Exec(usuario.Nome, usuario.Nick, usuario.Email, usuario.Senha, usuario.ContaPrivada())
 	if erro != nil {
-		return 0, erro
+		return 0, traduzirErro(erro)
 	}
 
 	ultimoIdInserido, erro := resultado.LastInsertId()
@@ -40,16 +42,27 @@ func (repositorio Usuarios) Criar(usuario modelos.Usuario) (uint64, error) {
 	return uint64(ultimoIdInserido), nil
 }
 
-// Buscar retorna usuários de acordo com filtros dados.
-func (repositorio Usuarios) Buscar(nomeOuNick string) ([]modelos.Usuario, error) {
+// Buscar retorna usuários de acordo com filtros dados, paginados por (criadoEm, id).
+// Usuários com bloqueio em relação ao usuário logado, em qualquer sentido, não são retornados.
+func (repositorio Usuarios) Buscar(nomeOuNick string, usuarioLogadoId uint64, paginacao modelos.Paginacao) ([]modelos.Usuario, *modelos.Cursor, error) {
 	nomeOuNick = fmt.Sprintf("%%%s%%", nomeOuNick) // %nomeOuNick% . O escape, neste caso, para % é %% e para a string é %s
+	cursorData, cursorId := paginacao.Posicao()
 
 	linhas, erro := repositorio.db.Query(
-		"select ID, nome, nick, email, criadoEm from usuarios where nome like ? or nick like ?",
+		`select ID, nome, nick, email, criadoEm, privado from usuarios
+		where (nome like ? or nick like ?)
+		and id not in (select bloqueado_id from bloqueios where usuario_id = ?)
+		and id not in (select usuario_id from bloqueios where bloqueado_id = ?)
+		and (? is null or (criadoEm, id) < (?, ?))
+		order by criadoEm desc, id desc
+		limit ?`,
 		nomeOuNick, nomeOuNick,
+		usuarioLogadoId, usuarioLogadoId,
+		cursorData, cursorData, cursorId,
+		paginacao.LimiteDaConsulta(),
 	)
 	if erro != nil {
-		return nil, erro
+		return nil, nil, erro
 	}
 	defer linhas.Close()
 
@@ -67,20 +80,28 @@ func (repositorio Usuarios) Buscar(nomeOuNick string) ([]modelos.Usuario, error)
 			&usuario.Nick,
 			&usuario.Email,
 			&usuario.CriadoEm,
+			&usuario.Privado,
 		); erro != nil {
-			return nil, erro
+			return nil, nil, erro
 		}
 
 		usuarios = append(usuarios, usuario)
 	}
 
-	return usuarios, nil
+	var proximoCursor *modelos.Cursor
+	if paginacao.TemProximaPagina(len(usuarios)) {
+		usuarios = usuarios[:paginacao.Limite]
+		ultimo := usuarios[len(usuarios)-1]
+		proximoCursor = &modelos.Cursor{CriadoEm: ultimo.CriadoEm, ID: ultimo.ID}
+	}
+
+	return usuarios, proximoCursor, nil
 }
 
 // BuscarPorId retorna dados de um usuário dado seu ID
 func (repositorio Usuarios) BuscarPorId(usuarioId uint64) (modelos.Usuario, error) {
 	linhas, erro := repositorio.db.Query(
-		"select ID, nome, nick, email, criadoEm from usuarios where id = ?", usuarioId,
+		"select ID, nome, nick, email, criadoEm, privado from usuarios where id = ?", usuarioId,
 	)
 	if erro != nil {
 		return modelos.Usuario{}, erro
@@ -96,6 +117,7 @@ func (repositorio Usuarios) BuscarPorId(usuarioId uint64) (modelos.Usuario, erro
 			&usuario.Nick,
 			&usuario.Email,
 			&usuario.CriadoEm,
+			&usuario.Privado,
 		); erro != nil {
 			return modelos.Usuario{}, erro
 		}
@@ -104,17 +126,21 @@ func (repositorio Usuarios) BuscarPorId(usuarioId uint64) (modelos.Usuario, erro
 	return usuario, nil
 }
 
-// Atualizar dados de um usuário no banco de dados
+// Atualizar dados de um usuário no banco de dados. A troca de email desfaz a verificação do email anterior.
 func (repositorio Usuarios) Atualizar(usuarioId uint64, usuario modelos.Usuario) error {
 
-	statement, erro := repositorio.db.Prepare(`update usuarios set nome=?, nick=?, email=? where id=?`)
+	// As atribuições do update são avaliadas da esquerda para a direita: verificadoEm é calculado
+	// antes de email receber o novo valor. privado só é alterado quando informado.
+	statement, erro := repositorio.db.Prepare(
+		`update usuarios set verificadoEm = if(email = ?, verificadoEm, null), nome=?, nick=?, email=?, privado=coalesce(?, privado) where id=?`,
+	)
 	if erro != nil {
 		return erro
 	}
 	defer statement.Close()
 
-	if _, erro := statement.Exec(usuario.Nome, usuario.Nick, usuario.Email, usuarioId); erro != nil {
-		return erro
+	if _, erro := statement.Exec(usuario.Email, usuario.Nome, usuario.Nick, usuario.Email, usuario.Privado, usuarioId); erro != nil {
+		return traduzirErro(erro)
 	}
 
 	return nil
@@ -135,10 +161,36 @@ func (repositorio Usuarios) RemoverUsuario(usuarioId uint64) error {
 	return nil
 }
 
-// BuscarPorEmail busca um usuario dado seu email e retorna Id/senha (hash)
+// NickEmUso indica se já existe usuário cadastrado com o nick informado
+func (repositorio Usuarios) NickEmUso(nick string) (bool, error) {
+	var emUso bool
+
+	if erro := repositorio.db.QueryRow(
+		"select exists(select 1 from usuarios where nick = ?)", nick,
+	).Scan(&emUso); erro != nil {
+		return false, erro
+	}
+
+	return emUso, nil
+}
+
+// EmailEmUso indica se já existe usuário cadastrado com o email informado
+func (repositorio Usuarios) EmailEmUso(email string) (bool, error) {
+	var emUso bool
+
+	if erro := repositorio.db.QueryRow(
+		"select exists(select 1 from usuarios where email = ?)", email,
+	).Scan(&emUso); erro != nil {
+		return false, erro
+	}
+
+	return emUso, nil
+}
+
+// BuscarPorEmail busca um usuario dado seu email e retorna Id/senha (hash), papel, suspensão e verificação
 func (repositorio Usuarios) BuscarPorEmail(email string) (modelos.Usuario, error) {
 	linha, erro := repositorio.db.Query(
-		"select ID, email, senha, criadoEm from usuarios where email = ?", email,
+		"select ID, email, senha, criadoEm, papel, suspensoEm, verificadoEm from usuarios where email = ?", email,
 	)
 	if erro != nil {
 		return modelos.Usuario{}, erro
@@ -153,6 +205,9 @@ func (repositorio Usuarios) BuscarPorEmail(email string) (modelos.Usuario, error
 			&usuario.Email,
 			&usuario.Senha,
 			&usuario.CriadoEm,
+			&usuario.Papel,
+			&usuario.SuspensoEm,
+			&usuario.VerificadoEm,
 		); erro != nil {
 			return modelos.Usuario{}, erro
 		}
@@ -197,17 +252,24 @@ func (repositorio Usuarios) PararDeSeguir(usuarioId, seguidorId uint64) error {
 	return nil
 }
 
-// BuscarSeguidores retorna todos os seguidores de um determinado usuário
-func (repositorio Usuarios) BuscarSeguidores(usuarioId uint64) ([]modelos.Usuario, error) {
+// BuscarSeguidores retorna os seguidores de um determinado usuário, dos mais recentes para os mais antigos.
+// A paginação usa a data em que o usuário passou a ser seguido e o id do seguidor.
+func (repositorio Usuarios) BuscarSeguidores(usuarioId uint64, paginacao modelos.Paginacao) ([]modelos.Usuario, *modelos.Cursor, error) {
+	cursorData, cursorId := paginacao.Posicao()
 
 	linhas, erro := repositorio.db.Query(
-		`SELECT u.id, u.nome , u.nick, u.email from seguidores s 
+		`SELECT u.id, u.nome , u.nick, u.email, s.criadoEm from seguidores s 
 		join usuarios u on u.id = s.seguidor_id  
-		WHERE s.usuario_id = ?`,
+		WHERE s.usuario_id = ?
+		and (? is null or (s.criadoEm, u.id) < (?, ?))
+		order by s.criadoEm desc, u.id desc
+		limit ?`,
 		usuarioId,
+		cursorData, cursorData, cursorId,
+		paginacao.LimiteDaConsulta(),
 	)
 	if erro != nil {
-		return nil, erro
+		return nil, nil, erro
 	}
 	defer linhas.Close()
 
@@ -215,36 +277,54 @@ func (repositorio Usuarios) BuscarSeguidores(usuarioId uint64) ([]modelos.Usuari
 	// Ao invés de utilizar a declaração de um slice (como acima, comentado)
 	// utiliza-se o make para garantir o retorno esperado de uma lista vazia.
 	usuarios := make([]modelos.Usuario, 0)
+	datasDoSeguimento := make([]time.Time, 0)
 
 	for linhas.Next() {
 		var usuario modelos.Usuario
+		var seguindoDesde time.Time
 
 		if erro = linhas.Scan(
 			&usuario.ID,
 			&usuario.Nome,
 			&usuario.Nick,
 			&usuario.Email,
+			&seguindoDesde,
 		); erro != nil {
-			return nil, erro
+			return nil, nil, erro
 		}
 
 		usuarios = append(usuarios, usuario)
+		datasDoSeguimento = append(datasDoSeguimento, seguindoDesde)
+	}
+
+	var proximoCursor *modelos.Cursor
+	if paginacao.TemProximaPagina(len(usuarios)) {
+		usuarios = usuarios[:paginacao.Limite]
+		ultimo := len(usuarios) - 1
+		proximoCursor = &modelos.Cursor{CriadoEm: datasDoSeguimento[ultimo], ID: usuarios[ultimo].ID}
 	}
 
-	return usuarios, nil
+	return usuarios, proximoCursor, nil
 }
 
-// BuscarSeguindo retorna todos os seguidores de um determinado usuário
-func (repositorio Usuarios) BuscarSeguindo(usuarioId uint64) ([]modelos.Usuario, error) {
+// BuscarSeguindo retorna os usuários seguidos por um determinado usuário, dos mais recentes para os mais antigos.
+// A paginação usa a data em que o usuário passou a seguir e o id do usuário seguido.
+func (repositorio Usuarios) BuscarSeguindo(usuarioId uint64, paginacao modelos.Paginacao) ([]modelos.Usuario, *modelos.Cursor, error) {
+	cursorData, cursorId := paginacao.Posicao()
 
 	linhas, erro := repositorio.db.Query(
-		`SELECT u.id, u.nome , u.nick, u.email from seguidores s 
-		join usuarios u on u.id = s.usuario_id   
-		WHERE s.seguidor_id = ?`,
+		`SELECT u.id, u.nome , u.nick, u.email, s.criadoEm from seguidores s 
+		join usuarios u on u.id = s.usuario_id  
+		WHERE s.seguidor_id = ?
+		and (? is null or (s.criadoEm, u.id) < (?, ?))
+		order by s.criadoEm desc, u.id desc
+		limit ?`,
 		usuarioId,
+		cursorData, cursorData, cursorId,
+		paginacao.LimiteDaConsulta(),
 	)
 	if erro != nil {
-		return nil, erro
+		return nil, nil, erro
 	}
 	defer linhas.Close()
 
@@ -252,23 +332,34 @@ func (repositorio Usuarios) BuscarSeguindo(usuarioId uint64) ([]modelos.Usuario,
 	// Ao invés de utilizar a declaração de um slice (como acima, comentado)
 	// utiliza-se o make para garantir o retorno esperado de uma lista vazia.
 	usuarios := make([]modelos.Usuario, 0)
+	datasDoSeguimento := make([]time.Time, 0)
 
 	for linhas.Next() {
 		var usuario modelos.Usuario
+		var seguindoDesde time.Time
 
 		if erro = linhas.Scan(
 			&usuario.ID,
 			&usuario.Nome,
 			&usuario.Nick,
 			&usuario.Email,
+			&seguindoDesde,
 		); erro != nil {
-			return nil, erro
+			return nil, nil, erro
 		}
 
 		usuarios = append(usuarios, usuario)
+		datasDoSeguimento = append(datasDoSeguimento, seguindoDesde)
 	}
 
-	return usuarios, nil
+	var proximoCursor *modelos.Cursor
+	if paginacao.TemProximaPagina(len(usuarios)) {
+		usuarios = usuarios[:paginacao.Limite]
+		ultimo := len(usuarios) - 1
+		proximoCursor = &modelos.Cursor{CriadoEm: datasDoSeguimento[ultimo], ID: usuarios[ultimo].ID}
+	}
+
+	return usuarios, proximoCursor, nil
 }
 
 func (repositorio Usuarios) BuscarSenha(usuarioId uint64) (string, error) {
@@ -306,4 +397,131 @@ func (repositorio Usuarios) AtualizarSenha(usuarioId uint64, senhaComHash string
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// BuscarSituacao retorna id, papel e suspensão do usuário, usados para emitir e renovar tokens.
+// Usuário inexistente resulta em ID zero.
+func (repositorio Usuarios) BuscarSituacao(usuarioId uint64) (modelos.Usuario, error) {
+	linha, erro := repositorio.db.Query(
+		"select ID, papel, suspensoEm from usuarios where id = ?", usuarioId,
+	)
+	if erro != nil {
+		return modelos.Usuario{}, erro
+	}
+	defer linha.Close()
+
+	var usuario modelos.Usuario
+
+	if linha.Next() {
+		if erro = linha.Scan(
+			&usuario.ID,
+			&usuario.Papel,
+			&usuario.SuspensoEm,
+		); erro != nil {
+			return modelos.Usuario{}, erro
+		}
+	}
+
+	return usuario, nil
+}
+
+// BuscarContas retorna todas as contas, com papel e suspensão, paginadas por (criadoEm, id). Uso administrativo.
+func (repositorio Usuarios) BuscarContas(paginacao modelos.Paginacao) ([]modelos.Usuario, *modelos.Cursor, error) {
+	cursorData, cursorId := paginacao.Posicao()
+
+	linhas, erro := repositorio.db.Query(
+		`select ID, nome, nick, email, criadoEm, privado, papel, suspensoEm from usuarios
+		where (? is null or (criadoEm, id) < (?, ?))
+		order by criadoEm desc, id desc
+		limit ?`,
+		cursorData, cursorData, cursorId,
+		paginacao.LimiteDaConsulta(),
+	)
+	if erro != nil {
+		return nil, nil, erro
+	}
+	defer linhas.Close()
+
+	usuarios := make([]modelos.Usuario, 0)
+
+	for linhas.Next() {
+		var usuario modelos.Usuario
+
+		if erro = linhas.Scan(
+			&usuario.ID,
+			&usuario.Nome,
+			&usuario.Nick,
+			&usuario.Email,
+			&usuario.CriadoEm,
+			&usuario.Privado,
+			&usuario.Papel,
+			&usuario.SuspensoEm,
+		); erro != nil {
+			return nil, nil, erro
+		}
+
+		usuarios = append(usuarios, usuario)
+	}
+
+	var proximoCursor *modelos.Cursor
+	if paginacao.TemProximaPagina(len(usuarios)) {
+		usuarios = usuarios[:paginacao.Limite]
+		ultimo := usuarios[len(usuarios)-1]
+		proximoCursor = &modelos.Cursor{CriadoEm: ultimo.CriadoEm, ID: ultimo.ID}
+	}
+
+	return usuarios, proximoCursor, nil
+}
+
+// Suspender marca a conta do usuário como suspensa, mantendo a data da primeira suspensão
+func (repositorio Usuarios) Suspender(usuarioId uint64) error {
+	statement, erro := repositorio.db.Prepare(
+		"update usuarios set suspensoEm = current_timestamp where id = ? and suspensoEm is null",
+	)
+	if erro != nil {
+		return erro
+	}
+	defer statement.Close()
+
+	if _, erro := statement.Exec(usuarioId); erro != nil {
+		return erro
+	}
+
+	return nil
+}
+
+// Reativar remove a suspensão da conta do usuário
+func (repositorio Usuarios) Reativar(usuarioId uint64) error {
+	statement, erro := repositorio.db.Prepare(
+		"update usuarios set suspensoEm = null where id = ?",
+	)
+	if erro != nil {
+		return erro
+	}
+	defer statement.Close()
+
+	if _, erro := statement.Exec(usuarioId); erro != nil {
+		return erro
+	}
+
+	return nil
+}
+
+// VerificarEmail marca o email do usuário como confirmado. Retorna false quando o email não é mais
+// o do usuário ou já estava verificado, o que torna cada token de verificação de uso único.
+func (repositorio Usuarios) VerificarEmail(usuarioId uint64, email string) (bool, error) {
+	resultado, erro := repositorio.db.Exec(
+		"update usuarios set verificadoEm = current_timestamp where id = ? and email = ? and verificadoEm is null",
+		usuarioId, email,
+	)
+	if erro != nil {
+		return false, erro
+	}
+
+	linhasAfetadas, erro := resultado.RowsAffected()
+	if erro != nil {
+		return false, erro
+	}
+
+	return linhasAfetadas > 0, nil
+}
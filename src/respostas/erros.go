@@ -0,0 +1,88 @@
+package respostas
+
+import (
+	"api/src/modelos"
+	"net/http"
+)
+
+// Códigos de erro estáveis, que os clientes podem usar para tratar cada situação
+const (
+	CodigoErroInterno              = "ERRO_INTERNO"
+	CodigoRequisicaoInvalida       = "REQUISICAO_INVALIDA"
+	CodigoParametroInvalido        = "PARAMETRO_INVALIDO"
+	CodigoDadosInvalidos           = "DADOS_INVALIDOS"
+	CodigoNaoAutenticado           = "NAO_AUTENTICADO"
+	CodigoTokenInvalido            = "TOKEN_INVALIDO"
+	CodigoTokenRevogado            = "TOKEN_REVOGADO"
+	CodigoCredenciaisInvalidas     = "CREDENCIAIS_INVALIDAS"
+	CodigoAcessoNegado             = "ACESSO_NEGADO"
+	CodigoPapelInsuficiente        = "PAPEL_INSUFICIENTE"
+	CodigoUsuarioSuspenso          = "USUARIO_SUSPENSO"
+	CodigoEmailNaoVerificado       = "EMAIL_NAO_VERIFICADO"
+	CodigoUsuarioBloqueado         = "USUARIO_BLOQUEADO"
+	CodigoContaPrivada             = "CONTA_PRIVADA"
+	CodigoNaoEncontrado            = "NAO_ENCONTRADO"
+	CodigoUsuarioNaoEncontrado     = "USUARIO_NAO_ENCONTRADO"
+	CodigoPublicacaoNaoEncontrada  = "PUBLICACAO_NAO_ENCONTRADA"
+	CodigoComentarioNaoEncontrado  = "COMENTARIO_NAO_ENCONTRADO"
+	CodigoDenunciaNaoEncontrada    = "DENUNCIA_NAO_ENCONTRADA"
+	CodigoSolicitacaoNaoEncontrada = "SOLICITACAO_NAO_ENCONTRADA"
+	CodigoConflito                 = "CONFLITO"
+	CodigoNickDuplicado            = "NICK_DUPLICADO"
+	CodigoEmailDuplicado           = "EMAIL_DUPLICADO"
+	CodigoDenunciaDuplicada        = "DENUNCIA_DUPLICADA"
+	CodigoDenunciaJaModerada       = "DENUNCIA_JA_MODERADA"
+	CodigoEmailJaVerificado        = "EMAIL_JA_VERIFICADO"
+	CodigoLimiteExcedido           = "LIMITE_EXCEDIDO"
+)
+
+// Erro é um erro de negócio da API: carrega um código estável e uma mensagem que pode ser exibida ao usuário
+type Erro struct {
+	Codigo   string
+	Mensagem string
+	Campos   []modelos.CampoInvalido
+}
+
+// NovoErro cria um erro de negócio da API
+func NovoErro(codigo, mensagem string) *Erro {
+	return &Erro{Codigo: codigo, Mensagem: mensagem}
+}
+
+func (erro *Erro) Error() string {
+	return erro.Mensagem
+}
+
+// Problema é o corpo das respostas de erro, no formato application/problem+json (RFC 7807),
+// estendido com o código do erro, os campos inválidos e o id da requisição
+type Problema struct {
+	Tipo         string                  `json:"type"`
+	Titulo       string                  `json:"title"`
+	Status       int                     `json:"status"`
+	Mensagem     string                  `json:"detail"`
+	Instancia    string                  `json:"instance,omitempty"`
+	Codigo       string                  `json:"codigo"`
+	Campos       []modelos.CampoInvalido `json:"campos,omitempty"`
+	RequisicaoId string                  `json:"requisicaoId,omitempty"`
+}
+
+// codigoPadrao define o código de erro quando o erro não traz um código próprio
+func codigoPadrao(statusCode int) string {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return CodigoRequisicaoInvalida
+	case http.StatusUnauthorized:
+		return CodigoNaoAutenticado
+	case http.StatusForbidden:
+		return CodigoAcessoNegado
+	case http.StatusNotFound:
+		return CodigoNaoEncontrado
+	case http.StatusConflict:
+		return CodigoConflito
+	case http.StatusUnprocessableEntity:
+		return CodigoRequisicaoInvalida
+	case http.StatusTooManyRequests:
+		return CodigoLimiteExcedido
+	default:
+		return CodigoErroInterno
+	}
+}
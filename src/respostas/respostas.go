@@ -1,8 +1,11 @@
 package respostas
 
 import (
+	"api/src/logs"
+	"api/src/modelos"
 	"encoding/json"
-	"log"
+	"errors"
+	"log/slog"
 	"net/http"
 )
 
@@ -18,7 +21,7 @@ func JSON(w http.ResponseWriter, statusCode int, dados interface{}, headers map[
 		w.WriteHeader(statusCode) // Tem que ser definido após os demais headers.
 		
 		if erro := json.NewEncoder(w).Encode(dados); erro != nil {
-			log.Fatal(erro)
+			slog.Error("falha ao escrever resposta", slog.Any("erro", erro))
 		}
 	} else { // else necessário para evitar warning log `http: superfluous response.WriteHeader call from api/src/respostas.JSON (respostas.go:xx)`
 		w.WriteHeader(statusCode)
@@ -26,23 +29,58 @@ func JSON(w http.ResponseWriter, statusCode int, dados interface{}, headers map[
 	
 }
 
-// ERRO formata a resposta de erro durante a execução da aplicação, sendo devolvida no response do request
-func ERRO(w http.ResponseWriter, statusCode int, erro error) {
-	// Aqui está sendo criado um struct anônimo que representa o erro.
-	// para fins de aprendizado, está sendo exposto erro interno.
-	// No mundo corporativo esta não é uma boa prática, sendo geralmente convencionado
-	// logar o erro e trocar a mensagem de erro para uma mensagem de erro negocial.
-	/* O struct utilizado aqui representa o seguinte código:
-	type Erro struct {
-		Erro string `json:"erro"`
+// ERRO formata a resposta de erro no formato application/problem+json (RFC 7807).
+// Erros da API (*Erro) e de validação (modelos.ErroDeValidacao) têm código e mensagem expostos ao cliente.
+// Demais erros com status 5xx são registrados em log e substituídos por uma mensagem genérica,
+// evitando expor detalhes internos (driver do banco, bcrypt, etc).
+func ERRO(w http.ResponseWriter, r *http.Request, statusCode int, erro error) {
+	problema := Problema{
+		Tipo:      "about:blank",
+		Titulo:    http.StatusText(statusCode),
+		Status:    statusCode,
+		Instancia: r.URL.Path,
 	}
-	E seu uso equivale a:
-	var erro Erro
-	erro.erro = erro.Error()
-	*/
-	JSON(w, statusCode, struct {
-		Erro string `json:"erro"`
-	}{
-		Erro: erro.Error(),
-	}, map[string] string{"content-type": "application/json"})
-}
\ No newline at end of file
+
+	if requisicao := logs.DoContexto(r.Context()); requisicao != nil {
+		problema.RequisicaoId = requisicao.Id
+	}
+
+	var erroDaApi *Erro
+	var erroDeValidacao modelos.ErroDeValidacao
+
+	switch {
+	case errors.As(erro, &erroDaApi):
+		problema.Codigo = erroDaApi.Codigo
+		problema.Mensagem = erroDaApi.Mensagem
+		problema.Campos = erroDaApi.Campos
+	case errors.As(erro, &erroDeValidacao):
+		problema.Codigo = CodigoDadosInvalidos
+		problema.Mensagem = erroDeValidacao.Error()
+		problema.Campos = erroDeValidacao.Campos
+	case statusCode >= http.StatusInternalServerError:
+		problema.Codigo = CodigoErroInterno
+		problema.Mensagem = "não foi possível processar a requisição"
+	default:
+		problema.Codigo = codigoPadrao(statusCode)
+		problema.Mensagem = erro.Error()
+	}
+
+	if statusCode >= http.StatusInternalServerError {
+		atributos := append(logs.Atributos(r.Context()), slog.Any("erro", erro))
+		slog.ErrorContext(r.Context(), "erro interno", atributos...)
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(statusCode)
+
+	if erro := json.NewEncoder(w).Encode(problema); erro != nil {
+		slog.Error("falha ao escrever resposta", slog.Any("erro", erro))
+	}
+}
+
+// Pagina é o envelope das listagens paginadas.
+// ProximoCursor deve ser enviado no parâmetro `cursor` para obter a página seguinte; vazio indica a última página.
+type Pagina struct {
+	Dados         interface{} `json:"dados"`
+	ProximoCursor string      `json:"proximoCursor,omitempty"`
+}
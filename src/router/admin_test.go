@@ -0,0 +1,71 @@
+package router_test
+
+import (
+	"api/src/modelos"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestRotasDeAdministradorExigemPapel(t *testing.T) {
+	api := novaApi(t)
+	ana := api.novoUsuario("ana")
+
+	api.esperar(api.requisitar(http.MethodGet, "/admin/usuarios", "", nil), http.StatusUnauthorized)
+	resposta := api.esperar(api.requisitar(http.MethodGet, "/admin/usuarios", ana.token, nil), http.StatusForbidden)
+	if codigo := resposta.codigo(t); codigo != "PAPEL_INSUFICIENTE" {
+		t.Fatalf("código %q, esperado PAPEL_INSUFICIENTE", codigo)
+	}
+
+	moderador := api.comoAdministrador(api.novoUsuario("moderador"))
+	contas := buscarPagina[modelos.Usuario](api, "/admin/usuarios", moderador.token).Dados
+	if len(contas) != 2 {
+		t.Fatalf("contas inesperadas: %+v", contas)
+	}
+}
+
+func TestRebaixamentoValeImediatamente(t *testing.T) {
+	api := novaApi(t)
+	ana := api.novoUsuario("ana")
+
+	// O token emitido antes da promoção já dá acesso, e o emitido antes do rebaixamento deixa de dar
+	api.esperar(api.requisitar(http.MethodGet, "/admin/usuarios", ana.token, nil), http.StatusForbidden)
+	api.comoAdministrador(ana)
+	api.esperar(api.requisitar(http.MethodGet, "/admin/usuarios", ana.token, nil), http.StatusOK)
+
+	api.definirPapel(ana, modelos.PapelUsuario)
+	api.esperar(api.requisitar(http.MethodGet, "/admin/usuarios", ana.token, nil), http.StatusForbidden)
+}
+
+func TestSuspensaoDeUsuario(t *testing.T) {
+	api := novaApi(t)
+	ana := api.novoUsuario("ana")
+	moderador := api.comoAdministrador(api.novoUsuario("moderador"))
+
+	api.esperar(api.requisitar(http.MethodPost, fmt.Sprintf("/admin/usuarios/%d/suspender", moderador.id), moderador.token, nil), http.StatusForbidden)
+	api.esperar(api.requisitar(http.MethodPost, "/admin/usuarios/999/suspender", moderador.token, nil), http.StatusNotFound)
+	api.esperar(api.requisitar(http.MethodPost, fmt.Sprintf("/admin/usuarios/%d/suspender", ana.id), moderador.token, nil), http.StatusNoContent)
+
+	// A suspensão encerra as sessões e impede novos logins e renovações
+	api.esperar(api.requisitar(http.MethodGet, "/publicacoes", ana.token, nil), http.StatusUnauthorized)
+	api.esperar(api.requisitar(http.MethodPost, "/token/renovar", "", map[string]string{"refreshToken": ana.refreshToken}), http.StatusUnauthorized)
+	resposta := api.esperar(api.requisitar(http.MethodPost, "/login", "", map[string]string{"email": ana.email, "senha": senhaDeTeste}), http.StatusForbidden)
+	if codigo := resposta.codigo(t); codigo != "USUARIO_SUSPENSO" {
+		t.Fatalf("código %q, esperado USUARIO_SUSPENSO", codigo)
+	}
+
+	api.esperar(api.requisitar(http.MethodPost, fmt.Sprintf("/admin/usuarios/%d/reativar", ana.id), moderador.token, nil), http.StatusNoContent)
+	api.entrar("ana")
+}
+
+func TestRemocaoDePublicacaoPorAdministrador(t *testing.T) {
+	api := novaApi(t)
+	ana := api.novoUsuario("ana")
+	moderador := api.comoAdministrador(api.novoUsuario("moderador"))
+	id := api.publicar(ana, "Removida", "publicação removida pela moderação")
+
+	api.esperar(api.requisitar(http.MethodDelete, fmt.Sprintf("/admin/publicacoes/%d", id), ana.token, nil), http.StatusForbidden)
+	api.esperar(api.requisitar(http.MethodDelete, fmt.Sprintf("/admin/publicacoes/%d", id), moderador.token, nil), http.StatusNoContent)
+	api.esperar(api.requisitar(http.MethodGet, fmt.Sprintf("/publicacoes/%d", id), ana.token, nil), http.StatusNotFound)
+	api.esperar(api.requisitar(http.MethodDelete, fmt.Sprintf("/admin/publicacoes/%d", id), moderador.token, nil), http.StatusNotFound)
+}
@@ -0,0 +1,66 @@
+package router_test
+
+import (
+	"api/src/modelos"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestBloqueioEscondePublicacoes(t *testing.T) {
+	api := novaApi(t)
+	ana := api.novoUsuario("ana")
+	bia := api.novoUsuario("bia")
+	id := api.publicar(ana, "Bloqueio", "publicação da ana")
+
+	api.esperar(api.requisitar(http.MethodPost, fmt.Sprintf("/usuarios/%d/bloquear", ana.id), bia.token, nil), http.StatusNoContent)
+
+	bloqueados := buscarPagina[modelos.Usuario](api, "/bloqueios", bia.token).Dados
+	if len(bloqueados) != 1 || bloqueados[0].ID != ana.id {
+		t.Fatalf("bloqueios inesperados: %+v", bloqueados)
+	}
+
+	// O bloqueio vale nos dois sentidos: nem quem bloqueou nem quem foi bloqueado interage com o outro
+	api.esperar(api.requisitar(http.MethodGet, fmt.Sprintf("/usuarios/%d", ana.id), bia.token, nil), http.StatusNotFound)
+	api.esperar(api.requisitar(http.MethodGet, fmt.Sprintf("/usuarios/%d", bia.id), ana.token, nil), http.StatusNotFound)
+
+	api.esperar(api.requisitar(http.MethodGet, fmt.Sprintf("/publicacoes/%d", id), bia.token, nil), http.StatusNotFound)
+	api.esperar(api.requisitar(http.MethodPost, fmt.Sprintf("/publicacoes/%d/curtir", id), bia.token, nil), http.StatusNotFound)
+	api.esperar(api.requisitar(http.MethodGet, fmt.Sprintf("/publicacoes/%d/curtidas", id), bia.token, nil), http.StatusNotFound)
+	api.esperar(api.requisitar(http.MethodGet, fmt.Sprintf("/publicacoes/%d/comentarios", id), bia.token, nil), http.StatusNotFound)
+	resposta := api.esperar(api.requisitar(http.MethodPost, fmt.Sprintf("/publicacoes/%d/comentarios", id), bia.token, map[string]string{
+		"conteudo": "não deveria ser criado",
+	}), http.StatusNotFound)
+	if codigo := resposta.codigo(t); codigo != "PUBLICACAO_NAO_ENCONTRADA" {
+		t.Fatalf("código %q, esperado PUBLICACAO_NAO_ENCONTRADA", codigo)
+	}
+
+	api.esperar(api.requisitar(http.MethodPost, fmt.Sprintf("/usuarios/%d/desbloquear", ana.id), bia.token, nil), http.StatusNoContent)
+	api.esperar(api.requisitar(http.MethodPost, fmt.Sprintf("/publicacoes/%d/curtir", id), bia.token, nil), http.StatusNoContent)
+	api.esperar(api.requisitar(http.MethodGet, fmt.Sprintf("/publicacoes/%d/comentarios", id), bia.token, nil), http.StatusOK)
+}
+
+func TestSilenciamentoRemoveDoFeed(t *testing.T) {
+	api := novaApi(t)
+	ana := api.novoUsuario("ana")
+	bia := api.novoUsuario("bia")
+	api.esperar(api.requisitar(http.MethodPost, fmt.Sprintf("/usuarios/%d/seguir", ana.id), bia.token, nil), http.StatusNoContent)
+	id := api.publicar(ana, "Silêncio", "publicação da ana")
+
+	if feed := buscarPagina[modelos.Publicacao](api, "/publicacoes", bia.token).Dados; len(feed) != 1 {
+		t.Fatalf("feed inesperado: %+v", feed)
+	}
+
+	api.esperar(api.requisitar(http.MethodPost, fmt.Sprintf("/usuarios/%d/silenciar", ana.id), bia.token, nil), http.StatusNoContent)
+	if feed := buscarPagina[modelos.Publicacao](api, "/publicacoes", bia.token).Dados; len(feed) != 0 {
+		t.Fatalf("publicação de usuário silenciado no feed: %+v", feed)
+	}
+
+	// Silenciar não impede o acesso direto, ao contrário do bloqueio
+	api.esperar(api.requisitar(http.MethodGet, fmt.Sprintf("/publicacoes/%d", id), bia.token, nil), http.StatusOK)
+
+	api.esperar(api.requisitar(http.MethodPost, fmt.Sprintf("/usuarios/%d/dessilenciar", ana.id), bia.token, nil), http.StatusNoContent)
+	if feed := buscarPagina[modelos.Publicacao](api, "/publicacoes", bia.token).Dados; len(feed) != 1 {
+		t.Fatalf("feed inesperado depois de dessilenciar: %+v", feed)
+	}
+}
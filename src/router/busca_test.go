@@ -0,0 +1,46 @@
+package router_test
+
+import (
+	"api/src/modelos"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestBuscaDePublicacoes(t *testing.T) {
+	api := novaApi(t)
+	ana := api.novoUsuario("ana")
+	bia := api.novoUsuario("bia")
+	caio := api.novoUsuario("caio")
+	api.tornarPrivada(caio)
+
+	maisRelevante := api.publicar(ana, "Golang", "concorrência em golang com <canais>")
+	menosRelevante := api.publicar(ana, "Dicas", "um pouco de golang")
+	api.publicar(ana, "Receita", "bolo de cenoura")
+	api.publicar(caio, "Privada", "golang golang golang")
+
+	api.esperar(api.requisitar(http.MethodGet, "/publicacoes/busca?q=", bia.token, nil), http.StatusBadRequest)
+	api.esperar(api.requisitar(http.MethodGet, "/publicacoes/busca?q="+url.QueryEscape(strings.Repeat("a", 101)), bia.token, nil), http.StatusBadRequest)
+
+	// Publicações de conta privada não aparecem para quem não a segue
+	primeira := buscarPagina[modelos.PublicacaoEncontrada](api, "/publicacoes/busca?q=golang&limite=1", bia.token)
+	if len(primeira.Dados) != 1 || primeira.Dados[0].ID != maisRelevante || primeira.ProximoCursor == "" {
+		t.Fatalf("primeira página inesperada: %+v", primeira)
+	}
+
+	trecho := primeira.Dados[0].Trecho
+	if !strings.Contains(trecho, "<mark>golang</mark>") || !strings.Contains(trecho, "&lt;canais&gt;") {
+		t.Fatalf("trecho sem destaque ou sem escape: %q", trecho)
+	}
+
+	segunda := buscarPagina[modelos.PublicacaoEncontrada](api, fmt.Sprintf("/publicacoes/busca?q=golang&limite=1&cursor=%s", primeira.ProximoCursor), bia.token)
+	if len(segunda.Dados) != 1 || segunda.Dados[0].ID != menosRelevante || segunda.ProximoCursor != "" {
+		t.Fatalf("segunda página inesperada: %+v", segunda)
+	}
+
+	if encontradas := buscarPagina[modelos.PublicacaoEncontrada](api, "/publicacoes/busca?q=golang", caio.token).Dados; len(encontradas) != 3 {
+		t.Fatalf("o autor deveria encontrar as próprias publicações: %+v", encontradas)
+	}
+}
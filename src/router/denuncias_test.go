@@ -0,0 +1,73 @@
+package router_test
+
+import (
+	"api/src/config"
+	"api/src/modelos"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestDenunciasOcultamPublicacao(t *testing.T) {
+	limiteOriginal := config.DenunciasParaOcultar
+	config.DenunciasParaOcultar = 2
+	t.Cleanup(func() { config.DenunciasParaOcultar = limiteOriginal })
+
+	api := novaApi(t)
+	ana := api.novoUsuario("ana")
+	bia := api.novoUsuario("bia")
+	caio := api.novoUsuario("caio")
+	moderador := api.comoAdministrador(api.novoUsuario("moderador"))
+	id := api.publicar(ana, "Denunciada", "publicação denunciada")
+
+	denunciar := fmt.Sprintf("/publicacoes/%d/denunciar", id)
+	denuncia := map[string]string{"motivo": modelos.MotivoSpam}
+
+	api.esperar(api.requisitar(http.MethodPost, denunciar, ana.token, denuncia), http.StatusForbidden)
+	api.esperar(api.requisitar(http.MethodPost, denunciar, bia.token, map[string]string{"motivo": "qualquer"}), http.StatusBadRequest)
+	api.esperar(api.requisitar(http.MethodPost, denunciar, bia.token, denuncia), http.StatusCreated)
+	resposta := api.esperar(api.requisitar(http.MethodPost, denunciar, bia.token, denuncia), http.StatusConflict)
+	if codigo := resposta.codigo(t); codigo != "DENUNCIA_DUPLICADA" {
+		t.Fatalf("código %q, esperado DENUNCIA_DUPLICADA", codigo)
+	}
+
+	perfil := fmt.Sprintf("/usuarios/%d/publicacoes", ana.id)
+	if publicacoes := buscarPagina[modelos.Publicacao](api, perfil, caio.token).Dados; len(publicacoes) != 1 {
+		t.Fatalf("publicação ocultada antes do limite: %+v", publicacoes)
+	}
+
+	api.esperar(api.requisitar(http.MethodPost, denunciar, caio.token, denuncia), http.StatusCreated)
+	if publicacoes := buscarPagina[modelos.Publicacao](api, perfil, caio.token).Dados; len(publicacoes) != 0 {
+		t.Fatalf("publicação não ocultada ao atingir o limite: %+v", publicacoes)
+	}
+
+	pendentes := buscarPagina[modelos.Denuncia](api, "/admin/denuncias", moderador.token).Dados
+	if len(pendentes) != 2 {
+		t.Fatalf("fila de moderação inesperada: %+v", pendentes)
+	}
+
+	// Descartar uma das denúncias deixa a publicação abaixo do limite e ela volta a aparecer
+	descartar := fmt.Sprintf("/admin/denuncias/%d/descartar", pendentes[0].ID)
+	api.esperar(api.requisitar(http.MethodPost, descartar, moderador.token, nil), http.StatusNoContent)
+	api.esperar(api.requisitar(http.MethodPost, descartar, moderador.token, nil), http.StatusConflict)
+	if publicacoes := buscarPagina[modelos.Publicacao](api, perfil, caio.token).Dados; len(publicacoes) != 1 {
+		t.Fatalf("publicação continua oculta depois do descarte: %+v", publicacoes)
+	}
+
+	api.esperar(api.requisitar(http.MethodPost, fmt.Sprintf("/admin/denuncias/%d/resolver", pendentes[1].ID), moderador.token, nil), http.StatusNoContent)
+	resolvidas := buscarPagina[modelos.Denuncia](api, "/admin/denuncias?situacao=resolvida", moderador.token).Dados
+	if len(resolvidas) != 1 || resolvidas[0].ModeradorId == nil || *resolvidas[0].ModeradorId != moderador.id {
+		t.Fatalf("denúncias resolvidas inesperadas: %+v", resolvidas)
+	}
+}
+
+func TestDenunciaDeUsuario(t *testing.T) {
+	api := novaApi(t)
+	ana := api.novoUsuario("ana")
+	bia := api.novoUsuario("bia")
+
+	denuncia := map[string]string{"motivo": modelos.MotivoFalsaIdentidade, "descricao": "perfil falso"}
+	api.esperar(api.requisitar(http.MethodPost, fmt.Sprintf("/usuarios/%d/denunciar", ana.id), ana.token, denuncia), http.StatusForbidden)
+	api.esperar(api.requisitar(http.MethodPost, fmt.Sprintf("/usuarios/%d/denunciar", ana.id), bia.token, denuncia), http.StatusCreated)
+	api.esperar(api.requisitar(http.MethodPost, "/usuarios/999/denunciar", bia.token, denuncia), http.StatusNotFound)
+}
@@ -0,0 +1,60 @@
+package router_test
+
+import (
+	"api/src/modelos"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestEntidadesDaPublicacao(t *testing.T) {
+	api := novaApi(t)
+	ana := api.novoUsuario("ana")
+	bia := api.novoUsuario("bia")
+
+	id := api.publicar(ana, "Entidades", "olá @bia e @ninguem, veja #GoLang.")
+
+	var publicacao modelos.Publicacao
+	api.esperar(api.requisitar(http.MethodGet, fmt.Sprintf("/publicacoes/%d", id), ana.token, nil), http.StatusOK).decodificar(t, &publicacao)
+
+	// Só menções a usuários existentes viram entidades; a hashtag vem normalizada e sem o ponto final
+	esperadas := []modelos.Entidade{
+		{Tipo: modelos.EntidadeMencao, Inicio: 4, Fim: 8, Texto: "bia", UsuarioId: bia.id},
+		{Tipo: modelos.EntidadeHashtag, Inicio: 26, Fim: 33, Texto: "golang"},
+	}
+	if len(publicacao.Entidades) != len(esperadas) {
+		t.Fatalf("entidades inesperadas: %+v", publicacao.Entidades)
+	}
+	for i, entidade := range publicacao.Entidades {
+		if entidade != esperadas[i] {
+			t.Fatalf("entidade %d: %+v, esperada %+v", i, entidade, esperadas[i])
+		}
+	}
+}
+
+func TestPublicacoesPorHashtag(t *testing.T) {
+	api := novaApi(t)
+	ana := api.novoUsuario("ana")
+	bia := api.novoUsuario("bia")
+
+	primeira := api.publicar(ana, "Primeira", "aprendendo #golang")
+	segunda := api.publicar(bia, "Segunda", "mais #GoLang e #testes")
+	api.publicar(ana, "Terceira", "sem hashtag")
+
+	api.esperar(api.requisitar(http.MethodGet, "/hashtags/123/publicacoes", ana.token, nil), http.StatusBadRequest)
+
+	pagina := buscarPagina[modelos.Publicacao](api, "/hashtags/%23GOLANG/publicacoes?limite=1", ana.token)
+	if len(pagina.Dados) != 1 || pagina.Dados[0].ID != segunda || pagina.ProximoCursor == "" {
+		t.Fatalf("primeira página inesperada: %+v", pagina)
+	}
+	pagina = buscarPagina[modelos.Publicacao](api, fmt.Sprintf("/hashtags/golang/publicacoes?limite=1&cursor=%s", pagina.ProximoCursor), ana.token)
+	if len(pagina.Dados) != 1 || pagina.Dados[0].ID != primeira || pagina.ProximoCursor != "" {
+		t.Fatalf("segunda página inesperada: %+v", pagina)
+	}
+
+	var emAlta []modelos.HashtagEmAlta
+	api.esperar(api.requisitar(http.MethodGet, "/hashtags/em-alta", ana.token, nil), http.StatusOK).decodificar(t, &emAlta)
+	if len(emAlta) != 2 || emAlta[0] != (modelos.HashtagEmAlta{Hashtag: "golang", Publicacoes: 2}) {
+		t.Fatalf("hashtags em alta inesperadas: %+v", emAlta)
+	}
+}
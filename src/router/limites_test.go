@@ -0,0 +1,65 @@
+package router_test
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestLimiteDeRequisicoesPorUsuario(t *testing.T) {
+	api := novaApi(t)
+	ana := api.novoUsuario("ana")
+	bia := api.novoUsuario("bia")
+
+	publicacao := map[string]string{"titulo": "Limite", "conteudo": "contando publicações"}
+
+	// POST /publicacoes usa o limite de criação: 10 por minuto para cada usuário
+	for restantes := 9; restantes >= 0; restantes-- {
+		resposta := api.esperar(api.requisitar(http.MethodPost, "/publicacoes", ana.token, publicacao), http.StatusCreated)
+		if resposta.cabecalho.Get("X-RateLimit-Limit") != "10" || resposta.cabecalho.Get("X-RateLimit-Remaining") != strconv.Itoa(restantes) {
+			t.Fatalf("cabeçalhos de limite inesperados: %v", resposta.cabecalho)
+		}
+		if resposta.cabecalho.Get("X-RateLimit-Reset") == "" {
+			t.Fatal("cabeçalho X-RateLimit-Reset ausente")
+		}
+	}
+
+	resposta := api.esperar(api.requisitar(http.MethodPost, "/publicacoes", ana.token, publicacao), http.StatusTooManyRequests)
+	if codigo := resposta.codigo(t); codigo != "LIMITE_EXCEDIDO" {
+		t.Fatalf("código %q, esperado LIMITE_EXCEDIDO", codigo)
+	}
+	if espera, erro := strconv.Atoi(resposta.cabecalho.Get("Retry-After")); erro != nil || espera <= 0 {
+		t.Fatalf("Retry-After inválido: %q", resposta.cabecalho.Get("Retry-After"))
+	}
+
+	// O limite é contado por usuário e por rota
+	api.esperar(api.requisitar(http.MethodPost, "/publicacoes", bia.token, publicacao), http.StatusCreated)
+	api.esperar(api.requisitar(http.MethodGet, "/publicacoes", ana.token, nil), http.StatusOK)
+}
+
+func TestLimiteDaConsultaDeDisponibilidade(t *testing.T) {
+	api := novaApi(t)
+
+	// Rota pública: o limite é contado por IP e impede a enumeração de nicks e emails
+	for i := 0; i < 20; i++ {
+		api.esperar(api.requisitar(http.MethodGet, fmt.Sprintf("/usuarios/disponibilidade?nick=usuario%d", i), "", nil), http.StatusOK)
+	}
+
+	resposta := api.esperar(api.requisitar(http.MethodGet, "/usuarios/disponibilidade?nick=outro", "", nil), http.StatusTooManyRequests)
+	if codigo := resposta.codigo(t); codigo != "LIMITE_EXCEDIDO" {
+		t.Fatalf("código %q, esperado LIMITE_EXCEDIDO", codigo)
+	}
+	if resposta.cabecalho.Get("Retry-After") == "" {
+		t.Fatal("cabeçalho Retry-After ausente")
+	}
+}
+
+func TestRotaSemLimite(t *testing.T) {
+	api := novaApi(t)
+
+	resposta := api.esperar(api.requisitar(http.MethodGet, "/healthz", "", nil), http.StatusOK)
+	if limite := resposta.cabecalho.Get("X-RateLimit-Limit"); limite != "" {
+		t.Fatalf("rota sem limite respondeu X-RateLimit-Limit %q", limite)
+	}
+}
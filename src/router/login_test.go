@@ -0,0 +1,129 @@
+package router_test
+
+import (
+	"api/src/config"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestLogin(t *testing.T) {
+	api := novaApi(t)
+	api.cadastrar("ana")
+
+	api.entrar("ana")
+
+	resposta := api.esperar(api.requisitar(http.MethodPost, "/login", "", map[string]string{
+		"email": "ana@devbook.test",
+		"senha": "senha-errada",
+	}), http.StatusUnauthorized)
+	if codigo := resposta.codigo(t); codigo != "CREDENCIAIS_INVALIDAS" {
+		t.Fatalf("código %s, esperado CREDENCIAIS_INVALIDAS", codigo)
+	}
+
+	// Email inexistente recebe a mesma resposta de senha errada
+	resposta = api.esperar(api.requisitar(http.MethodPost, "/login", "", map[string]string{
+		"email": "ninguem@devbook.test",
+		"senha": senhaDeTeste,
+	}), http.StatusUnauthorized)
+	if codigo := resposta.codigo(t); codigo != "CREDENCIAIS_INVALIDAS" {
+		t.Fatalf("código %s, esperado CREDENCIAIS_INVALIDAS", codigo)
+	}
+}
+
+func TestRotaAutenticadaSemToken(t *testing.T) {
+	api := novaApi(t)
+
+	api.esperar(api.requisitar(http.MethodGet, "/publicacoes", "", nil), http.StatusUnauthorized)
+	api.esperar(api.requisitar(http.MethodGet, "/publicacoes", "token-invalido", nil), http.StatusUnauthorized)
+}
+
+func TestRenovacaoDeToken(t *testing.T) {
+	api := novaApi(t)
+	ana := api.novoUsuario("ana")
+
+	var renovados struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refreshToken"`
+	}
+	api.esperar(api.requisitar(http.MethodPost, "/token/renovar", "", map[string]string{
+		"refreshToken": ana.refreshToken,
+	}), http.StatusOK).decodificar(t, &renovados)
+
+	api.esperar(api.requisitar(http.MethodGet, "/publicacoes", renovados.Token, nil), http.StatusOK)
+
+	// O token de acesso emitido com o refresh token anterior deixa de valer na rotação
+	api.esperar(api.requisitar(http.MethodGet, "/publicacoes", ana.token, nil), http.StatusUnauthorized)
+
+	// Reutilizar o refresh token já rotacionado encerra todas as sessões
+	api.esperar(api.requisitar(http.MethodPost, "/token/renovar", "", map[string]string{
+		"refreshToken": ana.refreshToken,
+	}), http.StatusUnauthorized)
+	api.esperar(api.requisitar(http.MethodGet, "/publicacoes", renovados.Token, nil), http.StatusUnauthorized)
+}
+
+func TestLogout(t *testing.T) {
+	api := novaApi(t)
+	ana := api.novoUsuario("ana")
+
+	api.esperar(api.requisitar(http.MethodPost, "/logout", ana.token, map[string]string{
+		"refreshToken": ana.refreshToken,
+	}), http.StatusNoContent)
+
+	api.esperar(api.requisitar(http.MethodGet, "/publicacoes", ana.token, nil), http.StatusUnauthorized)
+	api.esperar(api.requisitar(http.MethodPost, "/token/renovar", "", map[string]string{
+		"refreshToken": ana.refreshToken,
+	}), http.StatusUnauthorized)
+}
+
+// tentarLogin faz o login com a senha informada e retorna a resposta, sem verificar o status
+func (api *api) tentarLogin(email, senha string) resposta {
+	api.t.Helper()
+
+	return api.requisitar(http.MethodPost, "/login", "", map[string]string{"email": email, "senha": senha})
+}
+
+func TestBloqueioDaContaPorFalhasDeLogin(t *testing.T) {
+	api := novaApi(t)
+	api.cadastrar("ana")
+	api.cadastrar("bia")
+
+	// O sucesso zera as falhas da conta
+	for i := uint64(1); i < config.LoginMaxFalhasPorConta; i++ {
+		api.esperar(api.tentarLogin("ana@devbook.test", "senha-errada"), http.StatusUnauthorized)
+	}
+	api.entrar("ana")
+
+	for i := uint64(0); i < config.LoginMaxFalhasPorConta; i++ {
+		api.esperar(api.tentarLogin("ana@devbook.test", "senha-errada"), http.StatusUnauthorized)
+	}
+
+	// Bloqueada, a conta recusa até a senha certa, e o email é comparado sem diferenciar maiúsculas
+	resposta := api.esperar(api.tentarLogin("ANA@devbook.test", senhaDeTeste), http.StatusTooManyRequests)
+	if codigo := resposta.codigo(t); codigo != "LIMITE_EXCEDIDO" {
+		t.Fatalf("código %q, esperado LIMITE_EXCEDIDO", codigo)
+	}
+	espera, erro := strconv.Atoi(resposta.cabecalho.Get("Retry-After"))
+	if erro != nil || espera <= 0 || espera > int(config.LoginTempoBloqueio.Seconds()) {
+		t.Fatalf("Retry-After inválido: %q", resposta.cabecalho.Get("Retry-After"))
+	}
+
+	// As demais contas continuam acessíveis pelo mesmo IP
+	api.entrar("bia")
+}
+
+func TestBloqueioDoIPPorFalhasDeLogin(t *testing.T) {
+	maximoOriginal := config.LoginMaxFalhasPorIP
+	config.LoginMaxFalhasPorIP = 3
+	t.Cleanup(func() { config.LoginMaxFalhasPorIP = maximoOriginal })
+
+	api := novaApi(t)
+	api.cadastrar("ana")
+
+	// Emails inexistentes também contam, para que o bloqueio não revele quais contas existem
+	for _, email := range []string{"um@devbook.test", "dois@devbook.test", "tres@devbook.test"} {
+		api.esperar(api.tentarLogin(email, senhaDeTeste), http.StatusUnauthorized)
+	}
+
+	api.esperar(api.tentarLogin("ana@devbook.test", senhaDeTeste), http.StatusTooManyRequests)
+}
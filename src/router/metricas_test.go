@@ -0,0 +1,34 @@
+package router_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMetricas(t *testing.T) {
+	api := novaApi(t)
+	ana := api.novoUsuario("ana")
+	api.publicar(ana, "Métricas", "publicação contada")
+	api.esperar(api.requisitar(http.MethodGet, fmt.Sprintf("/usuarios/%d", ana.id), ana.token, nil), http.StatusOK)
+
+	corpo := string(api.esperar(api.requisitar(http.MethodGet, "/metrics", "", nil), http.StatusOK).corpo)
+
+	// As requisições são rotuladas pelo template da rota, não pelo caminho com o id
+	for _, esperado := range []string{
+		`http_requisicoes_total{metodo="GET",rota="/usuarios/{usuarioId}",status="200"}`,
+		`http_requisicoes_total{metodo="POST",rota="/login",status="200"}`,
+		"http_requisicao_duracao_segundos_bucket",
+		"devbook_logins_total",
+		"devbook_publicacoes_criadas_total",
+	} {
+		if !strings.Contains(corpo, esperado) {
+			t.Errorf("métrica ausente: %s", esperado)
+		}
+	}
+
+	if strings.Contains(corpo, fmt.Sprintf(`rota="/usuarios/%d"`, ana.id)) {
+		t.Error("métrica rotulada com o caminho bruto")
+	}
+}
@@ -0,0 +1,83 @@
+package router_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// camposInvalidos retorna os campos apontados em uma resposta de dados inválidos
+func camposInvalidos(t *testing.T, resposta resposta) []string {
+	t.Helper()
+
+	var problema struct {
+		Codigo string `json:"codigo"`
+		Campos []struct {
+			Campo string `json:"campo"`
+		} `json:"campos"`
+	}
+	resposta.decodificar(t, &problema)
+
+	if problema.Codigo != "DADOS_INVALIDOS" {
+		t.Fatalf("código %q, esperado DADOS_INVALIDOS", problema.Codigo)
+	}
+
+	campos := make([]string, 0, len(problema.Campos))
+	for _, campo := range problema.Campos {
+		campos = append(campos, campo.Campo)
+	}
+
+	return campos
+}
+
+func TestPoliticaDeSenhaNoCadastro(t *testing.T) {
+	api := novaApi(t)
+
+	senhas := map[string]string{
+		"curta":              "Ab1!",
+		"um tipo":            "abcdefghij",
+		"comum":              "Password1",
+		"igual ao nick":      "Carlos123",
+		"maior que o bcrypt": "Aa1" + strings.Repeat("a", 70),
+	}
+
+	for caso, senha := range senhas {
+		resposta := api.esperar(api.requisitar(http.MethodPost, "/usuarios", "", map[string]string{
+			"nome":  "Carlos",
+			"nick":  "carlos123",
+			"email": "carlos@devbook.test",
+			"senha": senha,
+		}), http.StatusBadRequest)
+
+		if campos := camposInvalidos(t, resposta); len(campos) == 0 || campos[0] != "senha" {
+			t.Fatalf("%s: campos inválidos %v, esperado senha", caso, campos)
+		}
+	}
+}
+
+func TestPoliticaDeSenhaNaTroca(t *testing.T) {
+	api := novaApi(t)
+	ana := api.novoUsuario("ana")
+	caminho := fmt.Sprintf("/usuarios/%d/atualizar-senha", ana.id)
+
+	resposta := api.esperar(api.requisitar(http.MethodPost, caminho, ana.token, map[string]string{
+		"atual": senhaDeTeste,
+		"nova":  "senha123",
+	}), http.StatusBadRequest)
+	if campos := camposInvalidos(t, resposta); len(campos) == 0 || campos[0] != "nova" {
+		t.Fatalf("campos inválidos %v, esperado nova", campos)
+	}
+
+	// A senha recusada não é gravada: a atual continua valendo
+	api.entrar("ana")
+
+	// Com a senha atual errada, a resposta é 401 mesmo que a nova descumpra a política
+	resposta = api.esperar(api.requisitar(http.MethodPost, caminho, ana.token, map[string]string{
+		"atual": "senha-errada",
+		"nova":  "senha123",
+	}), http.StatusUnauthorized)
+	if codigo := resposta.codigo(t); codigo != "CREDENCIAIS_INVALIDAS" {
+		t.Fatalf("código %q, esperado CREDENCIAIS_INVALIDAS", codigo)
+	}
+}
@@ -0,0 +1,199 @@
+package router_test
+
+import (
+	"api/src/modelos"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestCrudDePublicacoes(t *testing.T) {
+	api := novaApi(t)
+	ana := api.novoUsuario("ana")
+	bia := api.novoUsuario("bia")
+
+	id := api.publicar(ana, "Primeira", "conteúdo da primeira")
+
+	var publicacao modelos.Publicacao
+	api.esperar(api.requisitar(http.MethodGet, fmt.Sprintf("/publicacoes/%d", id), bia.token, nil), http.StatusOK).decodificar(t, &publicacao)
+	if publicacao.Titulo != "Primeira" || publicacao.AuthorId != ana.id || publicacao.AuthorNick != "ana" {
+		t.Fatalf("publicação inesperada: %+v", publicacao)
+	}
+
+	alteracao := map[string]string{"titulo": "Editada", "conteudo": "conteúdo editado"}
+	api.esperar(api.requisitar(http.MethodPatch, fmt.Sprintf("/publicacoes/%d", id), bia.token, alteracao), http.StatusForbidden)
+	api.esperar(api.requisitar(http.MethodPatch, fmt.Sprintf("/publicacoes/%d", id), ana.token, alteracao), http.StatusNoContent)
+
+	api.esperar(api.requisitar(http.MethodGet, fmt.Sprintf("/publicacoes/%d", id), bia.token, nil), http.StatusOK).decodificar(t, &publicacao)
+	if publicacao.Titulo != "Editada" || publicacao.Conteudo != "conteúdo editado" {
+		t.Fatalf("publicação não atualizada: %+v", publicacao)
+	}
+
+	api.esperar(api.requisitar(http.MethodDelete, fmt.Sprintf("/publicacoes/%d", id), bia.token, nil), http.StatusForbidden)
+	api.esperar(api.requisitar(http.MethodDelete, fmt.Sprintf("/publicacoes/%d", id), ana.token, nil), http.StatusNoContent)
+	api.esperar(api.requisitar(http.MethodGet, fmt.Sprintf("/publicacoes/%d", id), bia.token, nil), http.StatusNotFound)
+}
+
+func TestPublicacaoInvalida(t *testing.T) {
+	api := novaApi(t)
+	ana := api.novoUsuario("ana")
+
+	resposta := api.esperar(api.requisitar(http.MethodPost, "/publicacoes", ana.token, map[string]string{"titulo": "Sem conteúdo"}), http.StatusBadRequest)
+	if codigo := resposta.codigo(t); codigo != "DADOS_INVALIDOS" {
+		t.Fatalf("código %s, esperado DADOS_INVALIDOS", codigo)
+	}
+}
+
+func TestFeedPaginado(t *testing.T) {
+	api := novaApi(t)
+	ana := api.novoUsuario("ana")
+	bia := api.novoUsuario("bia")
+	caio := api.novoUsuario("caio")
+
+	api.esperar(api.requisitar(http.MethodPost, fmt.Sprintf("/usuarios/%d/seguir", bia.id), ana.token, nil), http.StatusNoContent)
+
+	esperadas := []uint64{
+		api.publicar(ana, "1", "primeira"),
+		api.publicar(bia, "2", "segunda"),
+		api.publicar(ana, "3", "terceira"),
+		api.publicar(bia, "4", "quarta"),
+		api.publicar(ana, "5", "quinta"),
+	}
+	// Quem a ana não segue não aparece no feed
+	api.publicar(caio, "6", "sexta")
+
+	var obtidas []uint64
+	cursor := ""
+	for paginas := 0; ; paginas++ {
+		if paginas > len(esperadas) {
+			t.Fatal("paginação não terminou")
+		}
+
+		pagina := buscarPagina[modelos.Publicacao](api, "/publicacoes?limite=2&cursor="+cursor, ana.token)
+		for _, publicacao := range pagina.Dados {
+			obtidas = append(obtidas, publicacao.ID)
+		}
+
+		if pagina.ProximoCursor == "" {
+			break
+		}
+		cursor = pagina.ProximoCursor
+	}
+
+	if len(obtidas) != len(esperadas) {
+		t.Fatalf("publicações %v, esperadas %v", obtidas, esperadas)
+	}
+	for i := range obtidas {
+		// Da mais recente para a mais antiga
+		if obtidas[i] != esperadas[len(esperadas)-1-i] {
+			t.Fatalf("publicações %v, esperadas em ordem inversa a %v", obtidas, esperadas)
+		}
+	}
+
+	api.esperar(api.requisitar(http.MethodGet, "/publicacoes?cursor=invalido", ana.token, nil), http.StatusBadRequest)
+}
+
+func TestCurtidas(t *testing.T) {
+	api := novaApi(t)
+	ana := api.novoUsuario("ana")
+	bia := api.novoUsuario("bia")
+	id := api.publicar(ana, "Curta", "curta esta publicação")
+
+	// Curtir duas vezes conta uma curtida só
+	api.esperar(api.requisitar(http.MethodPost, fmt.Sprintf("/publicacoes/%d/curtir", id), bia.token, nil), http.StatusNoContent)
+	api.esperar(api.requisitar(http.MethodPost, fmt.Sprintf("/publicacoes/%d/curtir", id), bia.token, nil), http.StatusNoContent)
+
+	// As listagens indicam se o usuário logado curtiu cada publicação
+	publicacao := buscarPagina[modelos.Publicacao](api, fmt.Sprintf("/usuarios/%d/publicacoes", ana.id), bia.token).Dados[0]
+	if publicacao.Curtidas != 1 || !publicacao.CurtidoPorMim {
+		t.Fatalf("curtidas inesperadas: %+v", publicacao)
+	}
+
+	curtidas := buscarPagina[modelos.Usuario](api, fmt.Sprintf("/publicacoes/%d/curtidas", id), ana.token)
+	if len(curtidas.Dados) != 1 || curtidas.Dados[0].ID != bia.id {
+		t.Fatalf("curtidas inesperadas: %+v", curtidas.Dados)
+	}
+
+	api.esperar(api.requisitar(http.MethodPost, fmt.Sprintf("/publicacoes/%d/descurtir", id), bia.token, nil), http.StatusNoContent)
+	publicacao = buscarPagina[modelos.Publicacao](api, fmt.Sprintf("/usuarios/%d/publicacoes", ana.id), bia.token).Dados[0]
+	if publicacao.Curtidas != 0 || publicacao.CurtidoPorMim {
+		t.Fatalf("descurtir não funcionou: %+v", publicacao)
+	}
+}
+
+func TestComentarios(t *testing.T) {
+	api := novaApi(t)
+	ana := api.novoUsuario("ana")
+	bia := api.novoUsuario("bia")
+	id := api.publicar(ana, "Comente", "comente esta publicação")
+
+	for _, conteudo := range []string{"primeiro", "segundo", "terceiro"} {
+		api.esperar(api.requisitar(http.MethodPost, fmt.Sprintf("/publicacoes/%d/comentarios", id), bia.token, map[string]string{
+			"conteudo": conteudo,
+		}), http.StatusCreated)
+	}
+
+	primeira := buscarPagina[modelos.Comentario](api, fmt.Sprintf("/publicacoes/%d/comentarios?limite=2", id), ana.token)
+	if len(primeira.Dados) != 2 || primeira.ProximoCursor == "" {
+		t.Fatalf("primeira página inesperada: %+v", primeira)
+	}
+	segunda := buscarPagina[modelos.Comentario](api, fmt.Sprintf("/publicacoes/%d/comentarios?limite=2&cursor=%s", id, primeira.ProximoCursor), ana.token)
+	if len(segunda.Dados) != 1 || segunda.ProximoCursor != "" {
+		t.Fatalf("segunda página inesperada: %+v", segunda)
+	}
+
+	comentarioId := segunda.Dados[0].ID
+	api.esperar(api.requisitar(http.MethodPatch, fmt.Sprintf("/comentarios/%d", comentarioId), ana.token, map[string]string{"conteudo": "x"}), http.StatusForbidden)
+	api.esperar(api.requisitar(http.MethodPatch, fmt.Sprintf("/comentarios/%d", comentarioId), bia.token, map[string]string{"conteudo": "editado"}), http.StatusNoContent)
+	api.esperar(api.requisitar(http.MethodDelete, fmt.Sprintf("/comentarios/%d", comentarioId), bia.token, nil), http.StatusNoContent)
+
+	publicacao := buscarPagina[modelos.Publicacao](api, "/publicacoes", ana.token).Dados[0]
+	if publicacao.TotalComentarios != 2 {
+		t.Fatalf("total de comentários %d, esperado 2", publicacao.TotalComentarios)
+	}
+}
+
+func TestPublicacaoOculta(t *testing.T) {
+	api := novaApi(t)
+	ana := api.novoUsuario("ana")
+	bia := api.novoUsuario("bia")
+	moderador := api.comoAdministrador(api.novoUsuario("moderador"))
+	id := api.publicar(ana, "Oculta", "publicação denunciada")
+
+	if erro := api.repositorios.Publicacoes.Ocultar(id); erro != nil {
+		t.Fatal(erro)
+	}
+
+	caminho := fmt.Sprintf("/publicacoes/%d", id)
+	if codigo := api.esperar(api.requisitar(http.MethodGet, caminho, bia.token, nil), http.StatusNotFound).codigo(t); codigo != "PUBLICACAO_NAO_ENCONTRADA" {
+		t.Fatalf("código %q, esperado PUBLICACAO_NAO_ENCONTRADA", codigo)
+	}
+	api.esperar(api.requisitar(http.MethodGet, caminho, ana.token, nil), http.StatusOK)
+	api.esperar(api.requisitar(http.MethodGet, caminho, moderador.token, nil), http.StatusOK)
+
+	// Curtidas, comentários e denúncias seguem a mesma regra do acesso direto
+	interacoes := []struct {
+		metodo  string
+		caminho string
+		corpo   interface{}
+	}{
+		{http.MethodPost, caminho + "/curtir", nil},
+		{http.MethodGet, caminho + "/curtidas", nil},
+		{http.MethodGet, caminho + "/comentarios", nil},
+		{http.MethodPost, caminho + "/comentarios", map[string]string{"conteudo": "olá"}},
+		{http.MethodPost, caminho + "/denunciar", map[string]string{"motivo": modelos.MotivoSpam}},
+	}
+	for _, interacao := range interacoes {
+		api.esperar(api.requisitar(interacao.metodo, interacao.caminho, bia.token, interacao.corpo), http.StatusNotFound)
+	}
+	api.esperar(api.requisitar(http.MethodGet, caminho+"/comentarios", ana.token, nil), http.StatusOK)
+	api.esperar(api.requisitar(http.MethodGet, caminho+"/curtidas", moderador.token, nil), http.StatusOK)
+
+	perfil := fmt.Sprintf("/usuarios/%d/publicacoes", ana.id)
+	if publicacoes := buscarPagina[modelos.Publicacao](api, perfil, bia.token).Dados; len(publicacoes) != 0 {
+		t.Fatalf("publicação oculta listada para outro usuário: %+v", publicacoes)
+	}
+	if publicacoes := buscarPagina[modelos.Publicacao](api, perfil, ana.token).Dados; len(publicacoes) != 1 {
+		t.Fatalf("o autor deveria ver a própria publicação oculta: %+v", publicacoes)
+	}
+}
@@ -0,0 +1,62 @@
+package rotas
+
+import (
+	"api/src/controllers"
+	"api/src/modelos"
+	"net/http"
+)
+
+// rotasAdmin retorna as rotas de moderação, restritas a administradores
+func rotasAdmin(controller *controllers.Controller) []Rota {
+	return []Rota{
+		{
+			URI:                "/admin/usuarios",
+			Metodo:             http.MethodGet,
+			Funcao:             controller.BuscarContas,
+			RequerAutenticacao: true,
+			RequerPapel:        modelos.PapelAdministrador,
+		},
+		{
+			URI:                "/admin/usuarios/{usuarioId}/suspender",
+			Metodo:             http.MethodPost,
+			Funcao:             controller.SuspenderUsuario,
+			RequerAutenticacao: true,
+			RequerPapel:        modelos.PapelAdministrador,
+		},
+		{
+			URI:                "/admin/usuarios/{usuarioId}/reativar",
+			Metodo:             http.MethodPost,
+			Funcao:             controller.ReativarUsuario,
+			RequerAutenticacao: true,
+			RequerPapel:        modelos.PapelAdministrador,
+		},
+		{
+			URI:                "/admin/publicacoes/{publicacaoId}",
+			Metodo:             http.MethodDelete,
+			Funcao:             controller.RemoverPublicacaoComoAdministrador,
+			RequerAutenticacao: true,
+			RequerPapel:        modelos.PapelAdministrador,
+		},
+		{
+			URI:                "/admin/denuncias",
+			Metodo:             http.MethodGet,
+			Funcao:             controller.BuscarDenuncias,
+			RequerAutenticacao: true,
+			RequerPapel:        modelos.PapelAdministrador,
+		},
+		{
+			URI:                "/admin/denuncias/{denunciaId}/resolver",
+			Metodo:             http.MethodPost,
+			Funcao:             controller.ResolverDenuncia,
+			RequerAutenticacao: true,
+			RequerPapel:        modelos.PapelAdministrador,
+		},
+		{
+			URI:                "/admin/denuncias/{denunciaId}/descartar",
+			Metodo:             http.MethodPost,
+			Funcao:             controller.DescartarDenuncia,
+			RequerAutenticacao: true,
+			RequerPapel:        modelos.PapelAdministrador,
+		},
+	}
+}
@@ -0,0 +1,52 @@
+package rotas
+
+import (
+	"api/src/controllers"
+	"net/http"
+)
+
+// rotasBloqueios retorna as rotas de bloqueio e silenciamento de usuários atendidas pelo controller informado
+func rotasBloqueios(controller *controllers.Controller) []Rota {
+	return []Rota{
+		{
+			URI:                "/usuarios/{usuarioId}/bloquear",
+			Metodo:             http.MethodPost,
+			Funcao:             controller.BloquearUsuario,
+			Limite:             limiteDeInteracao,
+			RequerAutenticacao: true,
+		},
+		{
+			URI:                "/usuarios/{usuarioId}/desbloquear",
+			Metodo:             http.MethodPost,
+			Funcao:             controller.DesbloquearUsuario,
+			Limite:             limiteDeInteracao,
+			RequerAutenticacao: true,
+		},
+		{
+			URI:                "/bloqueios",
+			Metodo:             http.MethodGet,
+			Funcao:             controller.BuscarBloqueados,
+			RequerAutenticacao: true,
+		},
+		{
+			URI:                "/usuarios/{usuarioId}/silenciar",
+			Metodo:             http.MethodPost,
+			Funcao:             controller.SilenciarUsuario,
+			Limite:             limiteDeInteracao,
+			RequerAutenticacao: true,
+		},
+		{
+			URI:                "/usuarios/{usuarioId}/dessilenciar",
+			Metodo:             http.MethodPost,
+			Funcao:             controller.DessilenciarUsuario,
+			Limite:             limiteDeInteracao,
+			RequerAutenticacao: true,
+		},
+		{
+			URI:                "/silenciados",
+			Metodo:             http.MethodGet,
+			Funcao:             controller.BuscarSilenciados,
+			RequerAutenticacao: true,
+		},
+	}
+}
@@ -0,0 +1,38 @@
+package rotas
+
+import (
+	"api/src/controllers"
+	"net/http"
+)
+
+// rotasComentarios retorna as rotas de comentários atendidas pelo controller informado
+func rotasComentarios(controller *controllers.Controller) []Rota {
+	return []Rota{
+		{
+			URI:                "/publicacoes/{publicacaoId}/comentarios",
+			Metodo:             http.MethodPost,
+			Funcao:             controller.CriarComentario,
+			Limite:             limiteDeCriacao,
+			RequerAutenticacao: true,
+		},
+		{
+			URI:                "/publicacoes/{publicacaoId}/comentarios",
+			Metodo:             http.MethodGet,
+			Funcao:             controller.BuscarComentarios,
+			RequerAutenticacao: true,
+		},
+		{
+			URI:                "/comentarios/{comentarioId}",
+			Metodo:             http.MethodPatch,
+			Funcao:             controller.AtualizarComentario,
+			Limite:             limiteDeCriacao,
+			RequerAutenticacao: true,
+		},
+		{
+			URI:                "/comentarios/{comentarioId}",
+			Metodo:             http.MethodDelete,
+			Funcao:             controller.RemoverComentario,
+			RequerAutenticacao: true,
+		},
+	}
+}
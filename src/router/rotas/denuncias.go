@@ -0,0 +1,26 @@
+package rotas
+
+import (
+	"api/src/controllers"
+	"net/http"
+)
+
+// rotasDenuncias retorna as rotas de denúncia de publicações e usuários atendidas pelo controller informado
+func rotasDenuncias(controller *controllers.Controller) []Rota {
+	return []Rota{
+		{
+			URI:                "/publicacoes/{publicacaoId}/denunciar",
+			Metodo:             http.MethodPost,
+			Funcao:             controller.DenunciarPublicacao,
+			Limite:             limiteDeCriacao,
+			RequerAutenticacao: true,
+		},
+		{
+			URI:                "/usuarios/{usuarioId}/denunciar",
+			Metodo:             http.MethodPost,
+			Funcao:             controller.DenunciarUsuario,
+			Limite:             limiteDeCriacao,
+			RequerAutenticacao: true,
+		},
+	}
+}
@@ -0,0 +1,24 @@
+package rotas
+
+import (
+	"api/src/controllers"
+	"net/http"
+)
+
+// rotasHashtags retorna as rotas de hashtags atendidas pelo controller informado
+func rotasHashtags(controller *controllers.Controller) []Rota {
+	return []Rota{
+		{
+			URI:                "/hashtags/em-alta",
+			Metodo:             http.MethodGet,
+			Funcao:             controller.BuscarHashtagsEmAlta,
+			RequerAutenticacao: true,
+		},
+		{
+			URI:                "/hashtags/{tag}/publicacoes",
+			Metodo:             http.MethodGet,
+			Funcao:             controller.BuscarPublicacoesPorHashtag,
+			RequerAutenticacao: true,
+		},
+	}
+}
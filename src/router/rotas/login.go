@@ -5,9 +5,28 @@ import (
 	"net/http"
 )
 
-var rotaLogin = Rota {
-	URI: "/login",
-	Metodo: http.MethodPost,
-	Funcao: controllers.Login,
-	RequerAutenticacao: false,
+// rotasLogin retorna as rotas de login e de gerenciamento da sessão atendidas pelo controller informado
+func rotasLogin(controller *controllers.Controller) []Rota {
+	return []Rota {
+		{
+			URI: "/login",
+			Metodo: http.MethodPost,
+			Funcao: controller.Login,
+			Limite: limitePublico,
+			RequerAutenticacao: false,
+		},
+		{
+			URI: "/token/renovar",
+			Metodo: http.MethodPost,
+			Funcao: controller.RenovarToken,
+			Limite: limitePublico,
+			RequerAutenticacao: false,
+		},
+		{
+			URI: "/logout",
+			Metodo: http.MethodPost,
+			Funcao: controller.Logout,
+			RequerAutenticacao: true,
+		},
+	}
 }
\ No newline at end of file
@@ -5,54 +5,73 @@ import (
 	"net/http"
 )
 
-
-var rotasPublicacoes = []Rota {
-	{
-		URI: "/publicacoes",
-		Metodo: http.MethodPost,
-		Funcao: controllers.CriarPublicacao,
-		RequerAutenticacao: true,
-	},
-	{
-		URI: "/publicacoes",
-		Metodo: http.MethodGet,
-		Funcao: controllers.BuscarPublicacoes,
-		RequerAutenticacao: true,
-	},
-	{
-		URI: "/publicacoes/{publicacaoId}",
-		Metodo: http.MethodGet,
-		Funcao: controllers.BuscarPublicacao,
-		RequerAutenticacao: true,
-	},
-	{
-		URI: "/publicacoes/{publicacaoId}",
-		Metodo: http.MethodPatch,
-		Funcao: controllers.AtualizarPublicacao,
-		RequerAutenticacao: true,
-	},
-	{
-		URI: "/publicacoes/{publicacaoId}",
-		Metodo: http.MethodDelete,
-		Funcao: controllers.RemoverPublicacao,
-		RequerAutenticacao: true,
-	},
-	{
-		URI: "/usuarios/{usuarioId}/publicacoes",
-		Metodo: http.MethodGet,
-		Funcao: controllers.BuscarPublicacoesPorUsuario,
-		RequerAutenticacao: true,
-	},
-	{
-		URI: "/publicacoes/{publicacaoId}/curtir",
-		Metodo: http.MethodPost,
-		Funcao: controllers.CurtirPublicacao,
-		RequerAutenticacao: true,
-	},
-	{
-		URI: "/publicacoes/{publicacaoId}/descurtir",
-		Metodo: http.MethodPost,
-		Funcao: controllers.DescurtirPublicacao,
-		RequerAutenticacao: true,
-	},
+// rotasPublicacoes retorna as rotas de publicações atendidas pelo controller informado
+func rotasPublicacoes(controller *controllers.Controller) []Rota {
+	return []Rota {
+		{
+			URI: "/publicacoes",
+			Metodo: http.MethodPost,
+			Funcao: controller.CriarPublicacao,
+			Limite: limiteDeCriacao,
+			RequerAutenticacao: true,
+		},
+		{
+			URI: "/publicacoes",
+			Metodo: http.MethodGet,
+			Funcao: controller.BuscarPublicacoes,
+			RequerAutenticacao: true,
+		},
+		{
+			// Registrada antes de /publicacoes/{publicacaoId} para que "busca" não seja lido como id
+			URI: "/publicacoes/busca",
+			Metodo: http.MethodGet,
+			Funcao: controller.PesquisarPublicacoes,
+			RequerAutenticacao: true,
+		},
+		{
+			URI: "/publicacoes/{publicacaoId}",
+			Metodo: http.MethodGet,
+			Funcao: controller.BuscarPublicacao,
+			RequerAutenticacao: true,
+		},
+		{
+			URI: "/publicacoes/{publicacaoId}",
+			Metodo: http.MethodPatch,
+			Funcao: controller.AtualizarPublicacao,
+			Limite: limiteDeCriacao,
+			RequerAutenticacao: true,
+		},
+		{
+			URI: "/publicacoes/{publicacaoId}",
+			Metodo: http.MethodDelete,
+			Funcao: controller.RemoverPublicacao,
+			RequerAutenticacao: true,
+		},
+		{
+			URI: "/usuarios/{usuarioId}/publicacoes",
+			Metodo: http.MethodGet,
+			Funcao: controller.BuscarPublicacoesPorUsuario,
+			RequerAutenticacao: true,
+		},
+		{
+			URI: "/publicacoes/{publicacaoId}/curtir",
+			Metodo: http.MethodPost,
+			Funcao: controller.CurtirPublicacao,
+			Limite: limiteDeInteracao,
+			RequerAutenticacao: true,
+		},
+		{
+			URI: "/publicacoes/{publicacaoId}/descurtir",
+			Metodo: http.MethodPost,
+			Funcao: controller.DescurtirPublicacao,
+			Limite: limiteDeInteracao,
+			RequerAutenticacao: true,
+		},
+		{
+			URI: "/publicacoes/{publicacaoId}/curtidas",
+			Metodo: http.MethodGet,
+			Funcao: controller.BuscarCurtidas,
+			RequerAutenticacao: true,
+		},
+	}
 }
\ No newline at end of file
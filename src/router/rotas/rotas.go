@@ -1,8 +1,13 @@
 package rotas
 
 import (
+	"api/src/controllers"
+	"api/src/limitador"
+	"api/src/metricas"
 	"api/src/middlewares"
+	"api/src/repositorios"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 )
@@ -13,24 +18,61 @@ type Rota struct {
 	Metodo string
 	Funcao func(http.ResponseWriter, *http.Request)
 	RequerAutenticacao bool
+	// RequerPapel restringe a rota a usuários com o papel informado (ex.: modelos.PapelAdministrador).
+	// Vazio significa qualquer papel. Implica autenticação.
+	RequerPapel string
+	// Limite restringe a quantidade de requisições por usuário autenticado (por IP nas rotas públicas).
+	// O valor zero significa sem limite.
+	Limite limitador.Limite
 }
 
-// Configurar coloca rotas dentro do router
-func Configurar(r *mux.Router) *mux.Router {
-	rotas := rotaUsuarios
-	rotas = append(rotas, rotaLogin)
-	rotas = append(rotas, rotasPublicacoes...)
+// Limites usados pelas rotas de escrita
+var (
+	// limiteDeCriacao vale para rotas que criam ou alteram conteúdo visto por outros usuários
+	limiteDeCriacao = limitador.Limite{Requisicoes: 10, Periodo: time.Minute}
+
+	// limiteDeInteracao vale para seguir, curtir, bloquear e demais interações entre usuários
+	limiteDeInteracao = limitador.Limite{Requisicoes: 60, Periodo: time.Minute}
+
+	// limitePublico vale para as rotas sem autenticação, como cadastro e login, contadas por IP
+	limitePublico = limitador.Limite{Requisicoes: 20, Periodo: time.Minute}
+)
+
+// Configurar coloca rotas dentro do router. O armazenamento guarda os baldes dos limites de cada rota;
+// tokens e usuarios são consultados pela autenticação e pela verificação de papel.
+func Configurar(r *mux.Router, controller *controllers.Controller, tokens repositorios.RepositorioDeTokens, usuarios repositorios.RepositorioDeUsuarios, armazenamento limitador.Armazenamento) *mux.Router {
+	rotas := rotasUsuarios(controller)
+	rotas = append(rotas, rotasLogin(controller)...)
+	rotas = append(rotas, rotasSenha(controller)...)
+	rotas = append(rotas, rotasPublicacoes(controller)...)
+	rotas = append(rotas, rotasHashtags(controller)...)
+	rotas = append(rotas, rotasComentarios(controller)...)
+	rotas = append(rotas, rotasDenuncias(controller)...)
+	rotas = append(rotas, rotasBloqueios(controller)...)
+	rotas = append(rotas, rotasSolicitacoes(controller)...)
+	rotas = append(rotas, rotasSaude(controller)...)
+	rotas = append(rotas, rotasAdmin(controller)...)
 
 	for _, rota := range rotas {
 
 		funcao := rota.Funcao
 
-		if rota.RequerAutenticacao {
-			funcao = middlewares.Autenticar(rota.Funcao)
+		if rota.Limite.Ativo() {
+			funcao = middlewares.LimitarTaxa(rota.URI, rota.Limite, armazenamento, funcao)
+		}
+
+		if rota.RequerPapel != "" {
+			funcao = middlewares.RequerPapel(rota.RequerPapel, funcao, usuarios)
+		}
+
+		if rota.RequerAutenticacao || rota.RequerPapel != "" {
+			funcao = middlewares.Autenticar(funcao, tokens)
 		}
 		
-		r.HandleFunc(rota.URI, middlewares.Logger(funcao)).Methods(rota.Metodo)
+		r.HandleFunc(rota.URI, middlewares.Logger(middlewares.Metricas(rota.URI, funcao))).Methods(rota.Metodo)
 	}
 
+	r.Handle("/metrics", metricas.Handler()).Methods(http.MethodGet)
+
 	return r
 }
\ No newline at end of file
@@ -0,0 +1,30 @@
+package rotas
+
+import (
+	"api/src/controllers"
+	"net/http"
+)
+
+// rotasSaude retorna as sondas de vida e prontidão e a identificação da versão, todas públicas
+func rotasSaude(controller *controllers.Controller) []Rota {
+	return []Rota{
+		{
+			URI:                "/healthz",
+			Metodo:             http.MethodGet,
+			Funcao:             controller.VerificarVida,
+			RequerAutenticacao: false,
+		},
+		{
+			URI:                "/readyz",
+			Metodo:             http.MethodGet,
+			Funcao:             controller.VerificarProntidao,
+			RequerAutenticacao: false,
+		},
+		{
+			URI:                "/versao",
+			Metodo:             http.MethodGet,
+			Funcao:             controller.BuscarVersao,
+			RequerAutenticacao: false,
+		},
+	}
+}
@@ -0,0 +1,26 @@
+package rotas
+
+import (
+	"api/src/controllers"
+	"net/http"
+)
+
+// rotasSenha retorna as rotas de recuperação de senha atendidas pelo controller informado
+func rotasSenha(controller *controllers.Controller) []Rota {
+	return []Rota{
+		{
+			URI:                "/senha/esqueci",
+			Metodo:             http.MethodPost,
+			Funcao:             controller.EsqueciSenha,
+			Limite:             limitePublico,
+			RequerAutenticacao: false,
+		},
+		{
+			URI:                "/senha/redefinir",
+			Metodo:             http.MethodPost,
+			Funcao:             controller.RedefinirSenha,
+			Limite:             limitePublico,
+			RequerAutenticacao: false,
+		},
+	}
+}
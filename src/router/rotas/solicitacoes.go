@@ -0,0 +1,32 @@
+package rotas
+
+import (
+	"api/src/controllers"
+	"net/http"
+)
+
+// rotasSolicitacoes retorna as rotas de pedidos para seguir contas privadas atendidas pelo controller informado
+func rotasSolicitacoes(controller *controllers.Controller) []Rota {
+	return []Rota{
+		{
+			URI:                "/solicitacoes",
+			Metodo:             http.MethodGet,
+			Funcao:             controller.BuscarSolicitacoes,
+			RequerAutenticacao: true,
+		},
+		{
+			URI:                "/solicitacoes/{usuarioId}/aceitar",
+			Metodo:             http.MethodPost,
+			Funcao:             controller.AceitarSolicitacao,
+			Limite:             limiteDeInteracao,
+			RequerAutenticacao: true,
+		},
+		{
+			URI:                "/solicitacoes/{usuarioId}/recusar",
+			Metodo:             http.MethodPost,
+			Funcao:             controller.RecusarSolicitacao,
+			Limite:             limiteDeInteracao,
+			RequerAutenticacao: true,
+		},
+	}
+}
@@ -5,63 +5,91 @@ import (
 	"net/http"
 )
 
-var rotaUsuarios = []Rota{
-	{
-		URI:                "/usuarios",
-		Metodo:             http.MethodPost,
-		Funcao:             controllers.CriarUsuario,
-		RequerAutenticacao: false,
-	},
-	{
-		URI:                "/usuarios",
-		Metodo:             http.MethodGet,
-		Funcao:             controllers.BuscarUsuarios,
-		RequerAutenticacao: true,
-	}, {
-		URI:                "/usuarios/{usuarioId}",
-		Metodo:             http.MethodGet,
-		Funcao:             controllers.BuscarUsuario,
-		RequerAutenticacao: true,
-	}, {
-		URI:                "/usuarios/{usuarioId}",
-		Metodo:             http.MethodPatch,
-		Funcao:             controllers.AtualizarUsuario,
-		RequerAutenticacao: true,
-	},
-	{
-		URI:                "/usuarios/{usuarioId}",
-		Metodo:             http.MethodDelete,
-		Funcao:             controllers.RemoverUsuario,
-		RequerAutenticacao: true,
-	},
-	{
-		URI:                "/usuarios/{usuarioId}/seguir",
-		Metodo:             http.MethodPost,
-		Funcao:             controllers.SeguirUsuario,
-		RequerAutenticacao: true,
-	},
-	{
-		URI:                "/usuarios/{usuarioId}/parar-de-seguir",
-		Metodo:             http.MethodPost,
-		Funcao:             controllers.PararDeSeguirUsuario,
-		RequerAutenticacao: true,
-	},
-	{
-		URI:                "/usuarios/{usuarioId}/seguidores",
-		Metodo:             http.MethodGet,
-		Funcao:             controllers.BuscarSeguidores,
-		RequerAutenticacao: true,
-	},
-	{
-		URI:                "/usuarios/{usuarioId}/seguindo",
-		Metodo:             http.MethodGet,
-		Funcao:             controllers.BuscarSeguidos,
-		RequerAutenticacao: true,
-	},
-	{
-		URI:                "/usuarios/{usuarioId}/atualizar-senha",
-		Metodo:             http.MethodPost,
-		Funcao:             controllers.AtualizarSenha,
-		RequerAutenticacao: true,
-	},
+// rotasUsuarios retorna as rotas de usuários atendidas pelo controller informado
+func rotasUsuarios(controller *controllers.Controller) []Rota {
+	return []Rota{
+		{
+			URI:                "/usuarios",
+			Metodo:             http.MethodPost,
+			Funcao:             controller.CriarUsuario,
+			Limite:             limitePublico,
+			RequerAutenticacao: false,
+		},
+		{
+			URI:                "/usuarios",
+			Metodo:             http.MethodGet,
+			Funcao:             controller.BuscarUsuarios,
+			RequerAutenticacao: true,
+		},
+		{
+			// Registrada antes de /usuarios/{usuarioId} para não ser capturada por ela
+			URI:                "/usuarios/disponibilidade",
+			Metodo:             http.MethodGet,
+			Funcao:             controller.VerificarDisponibilidade,
+			Limite:             limitePublico,
+			RequerAutenticacao: false,
+		},
+		{
+			URI:                "/usuarios/verificar-email",
+			Metodo:             http.MethodPost,
+			Funcao:             controller.VerificarEmail,
+			Limite:             limitePublico,
+			RequerAutenticacao: false,
+		},
+		{
+			URI:                "/usuarios/reenviar-verificacao",
+			Metodo:             http.MethodPost,
+			Funcao:             controller.ReenviarVerificacao,
+			Limite:             limitePublico,
+			RequerAutenticacao: false,
+		}, {
+			URI:                "/usuarios/{usuarioId}",
+			Metodo:             http.MethodGet,
+			Funcao:             controller.BuscarUsuario,
+			RequerAutenticacao: true,
+		}, {
+			URI:                "/usuarios/{usuarioId}",
+			Metodo:             http.MethodPatch,
+			Funcao:             controller.AtualizarUsuario,
+			RequerAutenticacao: true,
+		},
+		{
+			URI:                "/usuarios/{usuarioId}",
+			Metodo:             http.MethodDelete,
+			Funcao:             controller.RemoverUsuario,
+			RequerAutenticacao: true,
+		},
+		{
+			URI:                "/usuarios/{usuarioId}/seguir",
+			Metodo:             http.MethodPost,
+			Funcao:             controller.SeguirUsuario,
+			Limite:             limiteDeInteracao,
+			RequerAutenticacao: true,
+		},
+		{
+			URI:                "/usuarios/{usuarioId}/parar-de-seguir",
+			Metodo:             http.MethodPost,
+			Funcao:             controller.PararDeSeguirUsuario,
+			Limite:             limiteDeInteracao,
+			RequerAutenticacao: true,
+		},
+		{
+			URI:                "/usuarios/{usuarioId}/seguidores",
+			Metodo:             http.MethodGet,
+			Funcao:             controller.BuscarSeguidores,
+			RequerAutenticacao: true,
+		},
+		{
+			URI:                "/usuarios/{usuarioId}/seguindo",
+			Metodo:             http.MethodGet,
+			Funcao:             controller.BuscarSeguidos,
+			RequerAutenticacao: true,
+		},
+		{
+			URI:                "/usuarios/{usuarioId}/atualizar-senha",
+			Metodo:             http.MethodPost,
+			Funcao:             controller.AtualizarSenha,
+			RequerAutenticacao: true,
+		},
+	}
 }
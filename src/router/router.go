@@ -1,13 +1,71 @@
 package router
 
 import (
+	"api/src/config"
+	"api/src/controllers"
+	"api/src/email"
+	"api/src/limitador"
+	"api/src/repositorios"
 	"api/src/router/rotas"
+	"api/src/saude"
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 )
 
-//Gerar vai retornar um router com as rotas configuradas
-func Gerar() *mux.Router {
+//Gerar vai retornar um router com as rotas configuradas, usando os repositórios informados.
+// As tentativas de login e os limites de requisições ficam em memória, locais a esta instância da API;
+// os emails saem pelo mailer escolhido na configuração. O controller é retornado para que o encerramento
+// da API aguarde os emails ainda em envio.
+func Gerar(repositorios repositorios.Repositorios) (*mux.Router, *controllers.Controller) {
 	r := mux.NewRouter()
-	return rotas.Configurar(r)
-}
\ No newline at end of file
+	armazenamento := limitador.NovaMemoria()
+	controller := controllers.NovoController(repositorios, armazenamento, email.NovoMailer())
+	return rotas.Configurar(r, controller, repositorios.Tokens, repositorios.Usuarios, armazenamento), controller
+}
+
+// NovoServidor cria o servidor HTTP da API com os tempos limite da configuração, que impedem que clientes
+// lentos prendam conexões indefinidamente
+func NovoServidor(endereco string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              endereco,
+		Handler:           handler,
+		ReadHeaderTimeout: config.ServidorTempoLeituraCabecalho,
+		ReadTimeout:       config.ServidorTempoLeitura,
+		WriteTimeout:      config.ServidorTempoEscrita,
+		IdleTimeout:       config.ServidorTempoOcioso,
+		ErrorLog:          slog.NewLogLogger(slog.Default().Handler(), slog.LevelWarn),
+	}
+}
+
+// Encerrar sinaliza a API como não pronta, aguarda a retirada do balanceamento e conclui as requisições
+// em andamento e os emails ainda em envio dentro do prazo configurado. Retorna false se algo ficou
+// pendente ao fim do prazo.
+func Encerrar(servidor *http.Server, controller *controllers.Controller) bool {
+	saude.MarcarEncerrando()
+	time.Sleep(config.ServidorEsperaEncerramento)
+
+	ctx, cancelar := context.WithTimeout(context.Background(), config.ServidorTempoEncerramento)
+	defer cancelar()
+
+	concluido := true
+	if erro := servidor.Shutdown(ctx); erro != nil {
+		slog.Error("requisições não concluídas dentro do prazo de encerramento", slog.String("erro", erro.Error()))
+		servidor.Close()
+		concluido = false
+	}
+
+	if erro := controller.AguardarEnvios(ctx); erro != nil {
+		slog.Error("emails não enviados dentro do prazo de encerramento", slog.String("erro", erro.Error()))
+		concluido = false
+	}
+
+	if concluido {
+		slog.Info("API encerrada")
+	}
+
+	return concluido
+}
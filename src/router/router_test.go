@@ -0,0 +1,323 @@
+package router_test
+
+import (
+	"api/src/config"
+	"api/src/controllers"
+	"api/src/email"
+	"api/src/limitador"
+	"api/src/modelos"
+	"api/src/repositorios"
+	"api/src/repositorios/memoria"
+	"api/src/router/rotas"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// senhaDeTeste atende à política de senha e é usada por todos os usuários cadastrados nos testes
+const senhaDeTeste = "Senha-de-teste1"
+
+func TestMain(m *testing.M) {
+	config.SecretKey = []byte("chave-dos-testes")
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	os.Exit(m.Run())
+}
+
+// api é uma instância da API servida por httptest sobre os repositórios em memória
+type api struct {
+	t            *testing.T
+	servidor     *httptest.Server
+	repositorios repositorios.Repositorios
+	caixa        *caixaDeEntrada
+}
+
+// novaApi monta a API como router.Gerar, trocando o mailer por uma caixa de entrada que guarda as mensagens
+func novaApi(t *testing.T) *api {
+	t.Helper()
+
+	repositorios := memoria.NovosRepositorios()
+	armazenamento := limitador.NovaMemoria()
+	caixa := &caixaDeEntrada{}
+
+	controller := controllers.NovoController(repositorios, armazenamento, caixa)
+	servidor := httptest.NewServer(rotas.Configurar(mux.NewRouter(), controller, repositorios.Tokens, repositorios.Usuarios, armazenamento))
+	t.Cleanup(func() {
+		servidor.Close()
+		if erro := controller.AguardarEnvios(context.Background()); erro != nil {
+			t.Error(erro)
+		}
+	})
+
+	return &api{t: t, servidor: servidor, repositorios: repositorios, caixa: caixa}
+}
+
+// resposta guarda o que os testes verificam de uma resposta da API
+type resposta struct {
+	status    int
+	cabecalho http.Header
+	corpo     []byte
+}
+
+// decodificar lê o corpo JSON da resposta no destino informado
+func (resposta resposta) decodificar(t *testing.T, destino interface{}) {
+	t.Helper()
+
+	if erro := json.Unmarshal(resposta.corpo, destino); erro != nil {
+		t.Fatalf("corpo inválido (%v): %s", erro, resposta.corpo)
+	}
+}
+
+// codigo retorna o código do erro da resposta (application/problem+json)
+func (resposta resposta) codigo(t *testing.T) string {
+	t.Helper()
+
+	var problema struct {
+		Codigo string `json:"codigo"`
+	}
+	resposta.decodificar(t, &problema)
+
+	return problema.Codigo
+}
+
+// requisitar faz a requisição à API. O corpo, quando informado, é enviado como JSON.
+func (api *api) requisitar(metodo, caminho, token string, corpo interface{}) resposta {
+	api.t.Helper()
+
+	var leitor io.Reader
+	if corpo != nil {
+		dados, erro := json.Marshal(corpo)
+		if erro != nil {
+			api.t.Fatal(erro)
+		}
+		leitor = bytes.NewReader(dados)
+	}
+
+	requisicao, erro := http.NewRequest(metodo, api.servidor.URL+caminho, leitor)
+	if erro != nil {
+		api.t.Fatal(erro)
+	}
+
+	if token != "" {
+		requisicao.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resultado, erro := api.servidor.Client().Do(requisicao)
+	if erro != nil {
+		api.t.Fatal(erro)
+	}
+	defer resultado.Body.Close()
+
+	dados, erro := io.ReadAll(resultado.Body)
+	if erro != nil {
+		api.t.Fatal(erro)
+	}
+
+	return resposta{status: resultado.StatusCode, cabecalho: resultado.Header, corpo: dados}
+}
+
+// esperar falha o teste quando o status da resposta não é o esperado
+func (api *api) esperar(resposta resposta, status int) resposta {
+	api.t.Helper()
+
+	if resposta.status != status {
+		api.t.Fatalf("status %d, esperado %d: %s", resposta.status, status, resposta.corpo)
+	}
+
+	return resposta
+}
+
+// sessao é um usuário cadastrado e autenticado nos testes
+type sessao struct {
+	id           uint64
+	nick         string
+	email        string
+	token        string
+	refreshToken string
+}
+
+// cadastrar cria o usuário com o nick informado e retorna seu id
+func (api *api) cadastrar(nick string) uint64 {
+	api.t.Helper()
+
+	resposta := api.esperar(api.requisitar(http.MethodPost, "/usuarios", "", map[string]string{
+		"nome":  "Usuário " + nick,
+		"nick":  nick,
+		"email": nick + "@devbook.test",
+		"senha": senhaDeTeste,
+	}), http.StatusCreated)
+
+	return idDoLocation(api.t, resposta)
+}
+
+// entrar autentica o usuário com o nick informado
+func (api *api) entrar(nick string) sessao {
+	api.t.Helper()
+
+	resposta := api.esperar(api.requisitar(http.MethodPost, "/login", "", map[string]string{
+		"email": nick + "@devbook.test",
+		"senha": senhaDeTeste,
+	}), http.StatusOK)
+
+	var tokens struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refreshToken"`
+	}
+	resposta.decodificar(api.t, &tokens)
+
+	usuario, erro := api.repositorios.Usuarios.BuscarPorEmail(nick + "@devbook.test")
+	if erro != nil {
+		api.t.Fatal(erro)
+	}
+
+	return sessao{
+		id:           usuario.ID,
+		nick:         nick,
+		email:        nick + "@devbook.test",
+		token:        tokens.Token,
+		refreshToken: tokens.RefreshToken,
+	}
+}
+
+// novoUsuario cadastra e autentica um usuário
+func (api *api) novoUsuario(nick string) sessao {
+	api.t.Helper()
+
+	api.cadastrar(nick)
+	return api.entrar(nick)
+}
+
+// definirPapel altera o papel do usuário direto no repositório em memória, já que nenhuma rota da API o faz
+func (api *api) definirPapel(usuario sessao, papel string) {
+	api.t.Helper()
+
+	usuarios, ok := api.repositorios.Usuarios.(*memoria.Usuarios)
+	if !ok {
+		api.t.Fatalf("repositório de usuários inesperado: %T", api.repositorios.Usuarios)
+	}
+	usuarios.DefinirPapel(usuario.id, papel)
+}
+
+// comoAdministrador promove o usuário a administrador. O papel é lido do banco a cada requisição,
+// então a sessão continua valendo.
+func (api *api) comoAdministrador(usuario sessao) sessao {
+	api.t.Helper()
+
+	api.definirPapel(usuario, modelos.PapelAdministrador)
+	return usuario
+}
+
+// publicar cria uma publicação do usuário e retorna seu id
+func (api *api) publicar(autor sessao, titulo, conteudo string) uint64 {
+	api.t.Helper()
+
+	resposta := api.esperar(api.requisitar(http.MethodPost, "/publicacoes", autor.token, map[string]string{
+		"titulo":   titulo,
+		"conteudo": conteudo,
+	}), http.StatusCreated)
+
+	return idDoLocation(api.t, resposta)
+}
+
+// pagina é o formato das respostas paginadas (respostas.Pagina)
+type pagina[T any] struct {
+	Dados         []T    `json:"dados"`
+	ProximoCursor string `json:"proximoCursor"`
+}
+
+// buscarPagina faz um GET em uma rota paginada e decodifica a página
+func buscarPagina[T any](api *api, caminho, token string) pagina[T] {
+	api.t.Helper()
+
+	var resultado pagina[T]
+	api.esperar(api.requisitar(http.MethodGet, caminho, token, nil), http.StatusOK).decodificar(api.t, &resultado)
+
+	return resultado
+}
+
+// idDoLocation extrai o id do recurso criado do cabeçalho Location
+func idDoLocation(t *testing.T, resposta resposta) uint64 {
+	t.Helper()
+
+	id, erro := strconv.ParseUint(path.Base(resposta.cabecalho.Get("Location")), 10, 64)
+	if erro != nil {
+		t.Fatalf("location inválido: %q", resposta.cabecalho.Get("Location"))
+	}
+
+	return id
+}
+
+// caixaDeEntrada é um email.Mailer que guarda as mensagens enviadas
+type caixaDeEntrada struct {
+	mu        sync.Mutex
+	mensagens []email.Mensagem
+}
+
+func (caixa *caixaDeEntrada) Enviar(mensagem email.Mensagem) error {
+	caixa.mu.Lock()
+	defer caixa.mu.Unlock()
+
+	caixa.mensagens = append(caixa.mensagens, mensagem)
+	return nil
+}
+
+// aguardar espera até que a caixa tenha a quantidade de mensagens informada, já que alguns envios
+// são feitos fora da requisição, e retorna as mensagens
+func (caixa *caixaDeEntrada) aguardar(t *testing.T, quantidade int) []email.Mensagem {
+	t.Helper()
+
+	limite := time.Now().Add(2 * time.Second)
+	for {
+		caixa.mu.Lock()
+		mensagens := append([]email.Mensagem(nil), caixa.mensagens...)
+		caixa.mu.Unlock()
+
+		if len(mensagens) >= quantidade {
+			return mensagens
+		}
+
+		if time.Now().After(limite) {
+			t.Fatalf("%d mensagens recebidas, esperadas %d", len(mensagens), quantidade)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// quantidade retorna o número de mensagens recebidas até o momento
+func (caixa *caixaDeEntrada) quantidade() int {
+	caixa.mu.Lock()
+	defer caixa.mu.Unlock()
+
+	return len(caixa.mensagens)
+}
+
+// tokenDoEmail extrai o token do corpo da mensagem: sem frontend configurado, ele é a última linha do texto
+// que segue a instrução "Use o token abaixo"
+func tokenDoEmail(t *testing.T, mensagem email.Mensagem) string {
+	t.Helper()
+
+	_, depois, encontrado := strings.Cut(mensagem.Corpo, "Use o token abaixo")
+	if !encontrado {
+		t.Fatalf("email sem token: %q", mensagem.Corpo)
+	}
+
+	linhas := strings.Split(strings.TrimSpace(depois), "\n")
+	if len(linhas) < 3 {
+		t.Fatalf("email sem token: %q", mensagem.Corpo)
+	}
+
+	return strings.TrimSpace(linhas[2])
+}
@@ -0,0 +1,49 @@
+package router_test
+
+import (
+	"api/src/saude"
+	"net/http"
+	"testing"
+)
+
+func TestSondasDeSaude(t *testing.T) {
+	api := novaApi(t)
+
+	var corpo struct {
+		Status       string            `json:"status"`
+		Verificacoes map[string]string `json:"verificacoes"`
+	}
+	api.esperar(api.requisitar(http.MethodGet, "/healthz", "", nil), http.StatusOK).decodificar(t, &corpo)
+	if corpo.Status != "ok" {
+		t.Fatalf("status %q na sonda de vida", corpo.Status)
+	}
+
+	// A prontidão só é ligada depois que o servidor sobe e é desligada no encerramento
+	t.Cleanup(saude.MarcarEncerrando)
+	api.esperar(api.requisitar(http.MethodGet, "/readyz", "", nil), http.StatusServiceUnavailable)
+
+	saude.MarcarPronto()
+	api.esperar(api.requisitar(http.MethodGet, "/readyz", "", nil), http.StatusOK).decodificar(t, &corpo)
+	if corpo.Verificacoes["banco"] != "ok" || corpo.Verificacoes["migracoes"] != "ok" {
+		t.Fatalf("verificações inesperadas: %+v", corpo.Verificacoes)
+	}
+
+	saude.MarcarEncerrando()
+	api.esperar(api.requisitar(http.MethodGet, "/readyz", "", nil), http.StatusServiceUnavailable).decodificar(t, &corpo)
+	if corpo.Verificacoes["servidor"] != "encerrando" {
+		t.Fatalf("verificações inesperadas: %+v", corpo.Verificacoes)
+	}
+}
+
+func TestVersao(t *testing.T) {
+	api := novaApi(t)
+
+	var versao struct {
+		Commit      string `json:"commit"`
+		CompiladoEm string `json:"compiladoEm"`
+	}
+	api.esperar(api.requisitar(http.MethodGet, "/versao", "", nil), http.StatusOK).decodificar(t, &versao)
+	if versao.Commit == "" {
+		t.Fatal("versão sem commit")
+	}
+}
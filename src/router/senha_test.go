@@ -0,0 +1,38 @@
+package router_test
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRedefinicaoDeSenha(t *testing.T) {
+	api := novaApi(t)
+	ana := api.novoUsuario("ana")
+	api.caixa.aguardar(t, 1) // verificação do cadastro
+
+	api.esperar(api.requisitar(http.MethodPost, "/senha/esqueci", "", map[string]string{"email": "ninguem@devbook.test"}), http.StatusOK)
+	api.esperar(api.requisitar(http.MethodPost, "/senha/esqueci", "", map[string]string{"email": ana.email}), http.StatusOK)
+
+	mensagens := api.caixa.aguardar(t, 2)
+	if api.caixa.quantidade() != 2 || mensagens[1].Para != ana.email {
+		t.Fatalf("mensagens inesperadas: %+v", mensagens)
+	}
+	token := tokenDoEmail(t, mensagens[1])
+
+	// Senha fora da política é recusada sem consumir o token
+	api.esperar(api.requisitar(http.MethodPost, "/senha/redefinir", "", map[string]string{"token": token, "nova": "123"}), http.StatusBadRequest)
+
+	novaSenha := "Outra-senha-de-teste2"
+	api.esperar(api.requisitar(http.MethodPost, "/senha/redefinir", "", map[string]string{"token": token, "nova": novaSenha}), http.StatusNoContent)
+
+	resposta := api.esperar(api.requisitar(http.MethodPost, "/senha/redefinir", "", map[string]string{"token": token, "nova": "Mais-uma-senha3"}), http.StatusBadRequest)
+	if codigo := resposta.codigo(t); codigo != "TOKEN_INVALIDO" {
+		t.Fatalf("código %q, esperado TOKEN_INVALIDO", codigo)
+	}
+
+	// As sessões abertas antes da redefinição são encerradas
+	api.esperar(api.requisitar(http.MethodGet, "/publicacoes", ana.token, nil), http.StatusUnauthorized)
+
+	api.esperar(api.requisitar(http.MethodPost, "/login", "", map[string]string{"email": ana.email, "senha": senhaDeTeste}), http.StatusUnauthorized)
+	api.esperar(api.requisitar(http.MethodPost, "/login", "", map[string]string{"email": ana.email, "senha": novaSenha}), http.StatusOK)
+}
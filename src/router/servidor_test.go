@@ -0,0 +1,141 @@
+package router_test
+
+import (
+	"api/src/config"
+	"api/src/controllers"
+	"api/src/email"
+	"api/src/limitador"
+	"api/src/repositorios/memoria"
+	"api/src/router"
+	"api/src/router/rotas"
+	"api/src/saude"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// mailerLento é uma caixa de entrada cujos envios só terminam quando liberados
+type mailerLento struct {
+	caixaDeEntrada
+	liberar chan struct{}
+}
+
+func (mailer *mailerLento) Enviar(mensagem email.Mensagem) error {
+	<-mailer.liberar
+	return mailer.caixaDeEntrada.Enviar(mensagem)
+}
+
+// novaApiComServidor monta a API como novaApi, mas servida pelo servidor de router.NovoServidor,
+// e retorna também o controller, usado pelo encerramento
+func novaApiComServidor(t *testing.T, mailer email.Mailer) (*api, *controllers.Controller) {
+	t.Helper()
+
+	repositorios := memoria.NovosRepositorios()
+	armazenamento := limitador.NovaMemoria()
+
+	controller := controllers.NovoController(repositorios, armazenamento, mailer)
+	handler := rotas.Configurar(mux.NewRouter(), controller, repositorios.Tokens, repositorios.Usuarios, armazenamento)
+
+	servidor := httptest.NewUnstartedServer(handler)
+	servidor.Config = router.NovoServidor("", handler)
+	servidor.Start()
+	t.Cleanup(servidor.Close)
+
+	return &api{t: t, servidor: servidor, repositorios: repositorios}, controller
+}
+
+// alterarDuracao troca um tempo da configuração durante o teste
+func alterarDuracao(t *testing.T, duracao *time.Duration, valor time.Duration) {
+	original := *duracao
+	*duracao = valor
+	t.Cleanup(func() { *duracao = original })
+}
+
+func TestClienteLentoEDesconectado(t *testing.T) {
+	alterarDuracao(t, &config.ServidorTempoLeituraCabecalho, 100*time.Millisecond)
+	api, _ := novaApiComServidor(t, &caixaDeEntrada{})
+
+	conexao, erro := net.Dial("tcp", api.servidor.Listener.Addr().String())
+	if erro != nil {
+		t.Fatal(erro)
+	}
+	defer conexao.Close()
+
+	// Os cabeçalhos nunca são concluídos: o servidor fecha a conexão ao fim do prazo de leitura
+	if _, erro = fmt.Fprint(conexao, "GET /healthz HTTP/1.1\r\nHost: api\r\n"); erro != nil {
+		t.Fatal(erro)
+	}
+
+	conexao.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, erro = io.ReadAll(conexao)
+
+	var erroDeRede net.Error
+	if errors.As(erro, &erroDeRede) && erroDeRede.Timeout() {
+		t.Fatal("o servidor manteve a conexão do cliente lento")
+	}
+}
+
+func TestEncerramentoAguardaEnvios(t *testing.T) {
+	mailer := &mailerLento{liberar: make(chan struct{})}
+	api, controller := novaApiComServidor(t, mailer)
+
+	saude.MarcarPronto()
+	t.Cleanup(saude.MarcarEncerrando)
+
+	// O cadastro responde na hora e deixa o email de verificação em envio
+	api.cadastrar("ana")
+
+	encerrado := make(chan bool, 1)
+	go func() {
+		encerrado <- router.Encerrar(api.servidor.Config, controller)
+	}()
+
+	select {
+	case <-encerrado:
+		t.Fatal("a API encerrou antes de concluir o envio")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if saude.Pronto() {
+		t.Fatal("a API segue pronta durante o encerramento")
+	}
+	if _, erro := http.Get(api.servidor.URL + "/healthz"); erro == nil {
+		t.Fatal("a API aceitou uma requisição durante o encerramento")
+	}
+
+	close(mailer.liberar)
+
+	select {
+	case concluido := <-encerrado:
+		if !concluido {
+			t.Fatal("encerramento não concluído")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("a API não encerrou depois do envio")
+	}
+
+	if mensagens := mailer.aguardar(t, 1); mensagens[0].Para != "ana@devbook.test" {
+		t.Fatalf("mensagens inesperadas: %+v", mensagens)
+	}
+}
+
+func TestPrazoDeEncerramento(t *testing.T) {
+	alterarDuracao(t, &config.ServidorTempoEncerramento, 100*time.Millisecond)
+	mailer := &mailerLento{liberar: make(chan struct{})}
+	t.Cleanup(func() { close(mailer.liberar) })
+	api, controller := novaApiComServidor(t, mailer)
+
+	api.cadastrar("ana")
+
+	// Um envio preso não segura o encerramento além do prazo
+	if router.Encerrar(api.servidor.Config, controller) {
+		t.Fatal("encerramento concluído com um envio pendente")
+	}
+}
@@ -0,0 +1,100 @@
+package router_test
+
+import (
+	"api/src/modelos"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// tornarPrivada ativa a conta privada do usuário
+func (api *api) tornarPrivada(usuario sessao) {
+	api.t.Helper()
+
+	api.esperar(api.requisitar(http.MethodPatch, fmt.Sprintf("/usuarios/%d", usuario.id), usuario.token, map[string]interface{}{
+		"nome":    "Usuário " + usuario.nick,
+		"nick":    usuario.nick,
+		"email":   usuario.email,
+		"privado": true,
+	}), http.StatusNoContent)
+}
+
+func TestAtualizacaoMantemContaPrivada(t *testing.T) {
+	api := novaApi(t)
+	ana := api.novoUsuario("ana")
+	api.tornarPrivada(ana)
+
+	// Atualização sem o campo privado não torna a conta pública
+	api.esperar(api.requisitar(http.MethodPatch, fmt.Sprintf("/usuarios/%d", ana.id), ana.token, map[string]string{
+		"nome":  "Ana Maria",
+		"nick":  ana.nick,
+		"email": ana.email,
+	}), http.StatusNoContent)
+
+	var usuario modelos.Usuario
+	api.esperar(api.requisitar(http.MethodGet, fmt.Sprintf("/usuarios/%d", ana.id), ana.token, nil), http.StatusOK).decodificar(t, &usuario)
+	if usuario.Nome != "Ana Maria" || !usuario.ContaPrivada() {
+		t.Fatalf("usuário inesperado: %+v", usuario)
+	}
+
+	api.esperar(api.requisitar(http.MethodPatch, fmt.Sprintf("/usuarios/%d", ana.id), ana.token, map[string]interface{}{
+		"nome":    "Ana Maria",
+		"nick":    ana.nick,
+		"email":   ana.email,
+		"privado": false,
+	}), http.StatusNoContent)
+
+	usuario = modelos.Usuario{}
+	api.esperar(api.requisitar(http.MethodGet, fmt.Sprintf("/usuarios/%d", ana.id), ana.token, nil), http.StatusOK).decodificar(t, &usuario)
+	if usuario.ContaPrivada() {
+		t.Fatalf("conta deveria ser pública: %+v", usuario)
+	}
+}
+
+func TestContaPrivada(t *testing.T) {
+	api := novaApi(t)
+	ana := api.novoUsuario("ana")
+	bia := api.novoUsuario("bia")
+	api.tornarPrivada(ana)
+	id := api.publicar(ana, "Privada", "somente para seguidores")
+
+	restritas := []struct {
+		metodo  string
+		caminho string
+		corpo   interface{}
+	}{
+		{http.MethodGet, fmt.Sprintf("/usuarios/%d/publicacoes", ana.id), nil},
+		{http.MethodGet, fmt.Sprintf("/publicacoes/%d", id), nil},
+		{http.MethodPost, fmt.Sprintf("/publicacoes/%d/curtir", id), nil},
+		{http.MethodGet, fmt.Sprintf("/publicacoes/%d/curtidas", id), nil},
+		{http.MethodGet, fmt.Sprintf("/publicacoes/%d/comentarios", id), nil},
+		{http.MethodPost, fmt.Sprintf("/publicacoes/%d/comentarios", id), map[string]string{"conteudo": "olá"}},
+	}
+
+	for _, rota := range restritas {
+		resposta := api.esperar(api.requisitar(rota.metodo, rota.caminho, bia.token, rota.corpo), http.StatusForbidden)
+		if codigo := resposta.codigo(t); codigo != "CONTA_PRIVADA" {
+			t.Fatalf("%s %s: código %q, esperado CONTA_PRIVADA", rota.metodo, rota.caminho, codigo)
+		}
+	}
+
+	// Seguir conta privada cria uma solicitação, que precisa ser aceita
+	api.esperar(api.requisitar(http.MethodPost, fmt.Sprintf("/usuarios/%d/seguir", ana.id), bia.token, nil), http.StatusAccepted)
+	solicitacoes := buscarPagina[modelos.Usuario](api, "/solicitacoes", ana.token).Dados
+	if len(solicitacoes) != 1 || solicitacoes[0].ID != bia.id {
+		t.Fatalf("solicitações inesperadas: %+v", solicitacoes)
+	}
+	api.esperar(api.requisitar(http.MethodGet, fmt.Sprintf("/publicacoes/%d", id), bia.token, nil), http.StatusForbidden)
+
+	api.esperar(api.requisitar(http.MethodPost, fmt.Sprintf("/solicitacoes/%d/aceitar", bia.id), ana.token, nil), http.StatusNoContent)
+	for _, rota := range restritas {
+		status := http.StatusOK
+		if rota.metodo == http.MethodPost {
+			status = http.StatusNoContent
+			if rota.corpo != nil {
+				status = http.StatusCreated
+			}
+		}
+		api.esperar(api.requisitar(rota.metodo, rota.caminho, bia.token, rota.corpo), status)
+	}
+}
@@ -0,0 +1,174 @@
+package router_test
+
+import (
+	"api/src/modelos"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestCadastroDeUsuario(t *testing.T) {
+	api := novaApi(t)
+
+	id := api.cadastrar("ana")
+	ana := api.entrar("ana")
+
+	var usuario modelos.Usuario
+	api.esperar(api.requisitar(http.MethodGet, fmt.Sprintf("/usuarios/%d", id), ana.token, nil), http.StatusOK).decodificar(t, &usuario)
+
+	if usuario.ID != id || usuario.Nick != "ana" || usuario.Email != "ana@devbook.test" {
+		t.Fatalf("usuário inesperado: %+v", usuario)
+	}
+	if usuario.Senha != "" {
+		t.Fatal("a senha não deve ser devolvida")
+	}
+}
+
+func TestCadastroComDadosInvalidos(t *testing.T) {
+	api := novaApi(t)
+
+	resposta := api.esperar(api.requisitar(http.MethodPost, "/usuarios", "", map[string]string{
+		"nick":  "ana",
+		"email": "email-invalido",
+		"senha": senhaDeTeste,
+	}), http.StatusBadRequest)
+
+	var problema struct {
+		Codigo string                  `json:"codigo"`
+		Campos []modelos.CampoInvalido `json:"campos"`
+	}
+	resposta.decodificar(t, &problema)
+
+	if problema.Codigo != "DADOS_INVALIDOS" || len(problema.Campos) != 2 {
+		t.Fatalf("esperados os campos nome e email: %s", resposta.corpo)
+	}
+}
+
+func TestCadastroDuplicado(t *testing.T) {
+	api := novaApi(t)
+	api.cadastrar("ana")
+
+	casos := []struct {
+		nick, email, codigo string
+	}{
+		{"ana", "outra@devbook.test", "NICK_DUPLICADO"},
+		{"outra", "ana@devbook.test", "EMAIL_DUPLICADO"},
+	}
+
+	for _, caso := range casos {
+		resposta := api.esperar(api.requisitar(http.MethodPost, "/usuarios", "", map[string]string{
+			"nome":  "Outra",
+			"nick":  caso.nick,
+			"email": caso.email,
+			"senha": senhaDeTeste,
+		}), http.StatusConflict)
+
+		if codigo := resposta.codigo(t); codigo != caso.codigo {
+			t.Errorf("código %s, esperado %s", codigo, caso.codigo)
+		}
+	}
+}
+
+func TestAtualizacaoDeUsuario(t *testing.T) {
+	api := novaApi(t)
+	ana := api.novoUsuario("ana")
+	bia := api.novoUsuario("bia")
+
+	dados := map[string]string{"nome": "Ana Maria", "nick": "anamaria", "email": "ana@devbook.test"}
+
+	api.esperar(api.requisitar(http.MethodPatch, fmt.Sprintf("/usuarios/%d", ana.id), bia.token, dados), http.StatusForbidden)
+	api.esperar(api.requisitar(http.MethodPatch, fmt.Sprintf("/usuarios/%d", ana.id), ana.token, dados), http.StatusNoContent)
+
+	var usuario modelos.Usuario
+	api.esperar(api.requisitar(http.MethodGet, fmt.Sprintf("/usuarios/%d", ana.id), bia.token, nil), http.StatusOK).decodificar(t, &usuario)
+	if usuario.Nome != "Ana Maria" || usuario.Nick != "anamaria" {
+		t.Fatalf("usuário não atualizado: %+v", usuario)
+	}
+
+	dados["nick"] = "bia"
+	resposta := api.esperar(api.requisitar(http.MethodPatch, fmt.Sprintf("/usuarios/%d", ana.id), ana.token, dados), http.StatusConflict)
+	if codigo := resposta.codigo(t); codigo != "NICK_DUPLICADO" {
+		t.Fatalf("código %s, esperado NICK_DUPLICADO", codigo)
+	}
+}
+
+func TestRemocaoDeUsuario(t *testing.T) {
+	api := novaApi(t)
+	ana := api.novoUsuario("ana")
+	bia := api.novoUsuario("bia")
+	api.publicar(ana, "Olá", "primeira publicação")
+
+	api.esperar(api.requisitar(http.MethodDelete, fmt.Sprintf("/usuarios/%d", ana.id), bia.token, nil), http.StatusForbidden)
+	api.esperar(api.requisitar(http.MethodDelete, fmt.Sprintf("/usuarios/%d", ana.id), ana.token, nil), http.StatusNoContent)
+
+	api.esperar(api.requisitar(http.MethodGet, fmt.Sprintf("/usuarios/%d", ana.id), bia.token, nil), http.StatusNotFound)
+	if publicacoes := buscarPagina[modelos.Publicacao](api, "/publicacoes", bia.token); len(publicacoes.Dados) != 0 {
+		t.Fatal("as publicações do usuário removido devem ser removidas em cascata")
+	}
+}
+
+func TestSeguidoresPaginados(t *testing.T) {
+	api := novaApi(t)
+	ana := api.novoUsuario("ana")
+
+	seguidores := []sessao{api.novoUsuario("bia"), api.novoUsuario("caio"), api.novoUsuario("davi")}
+	for _, seguidor := range seguidores {
+		api.esperar(api.requisitar(http.MethodPost, fmt.Sprintf("/usuarios/%d/seguir", ana.id), seguidor.token, nil), http.StatusNoContent)
+	}
+
+	primeira := buscarPagina[modelos.Usuario](api, fmt.Sprintf("/usuarios/%d/seguidores?limite=2", ana.id), ana.token)
+	if len(primeira.Dados) != 2 || primeira.ProximoCursor == "" {
+		t.Fatalf("primeira página inesperada: %+v", primeira)
+	}
+
+	segunda := buscarPagina[modelos.Usuario](api, fmt.Sprintf("/usuarios/%d/seguidores?limite=2&cursor=%s", ana.id, primeira.ProximoCursor), ana.token)
+	if len(segunda.Dados) != 1 || segunda.ProximoCursor != "" {
+		t.Fatalf("segunda página inesperada: %+v", segunda)
+	}
+
+	// Do seguidor mais recente para o mais antigo
+	if primeira.Dados[0].ID != seguidores[2].id || segunda.Dados[0].ID != seguidores[0].id {
+		t.Fatalf("ordem inesperada: %+v %+v", primeira.Dados, segunda.Dados)
+	}
+
+	seguindo := buscarPagina[modelos.Usuario](api, fmt.Sprintf("/usuarios/%d/seguindo", seguidores[0].id), ana.token)
+	if len(seguindo.Dados) != 1 || seguindo.Dados[0].ID != ana.id {
+		t.Fatalf("seguindo inesperado: %+v", seguindo.Dados)
+	}
+}
+
+func TestTrocaDeSenhaEncerraSessoes(t *testing.T) {
+	api := novaApi(t)
+	api.cadastrar("ana")
+
+	// Sessão em que o refresh token já foi renovado: o token de acesso anterior não pode sobreviver à troca
+	celular := api.entrar("ana")
+	var renovados struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refreshToken"`
+	}
+	api.esperar(api.requisitar(http.MethodPost, "/token/renovar", "", map[string]string{
+		"refreshToken": celular.refreshToken,
+	}), http.StatusOK).decodificar(t, &renovados)
+
+	computador := api.entrar("ana")
+
+	api.esperar(api.requisitar(http.MethodPost, fmt.Sprintf("/usuarios/%d/atualizar-senha", computador.id), computador.token, map[string]string{
+		"atual": senhaDeTeste,
+		"nova":  "Outra-senha-forte2",
+	}), http.StatusOK)
+
+	for _, token := range []string{celular.token, renovados.Token, computador.token} {
+		api.esperar(api.requisitar(http.MethodGet, "/publicacoes", token, nil), http.StatusUnauthorized)
+	}
+	for _, refreshToken := range []string{renovados.RefreshToken, computador.refreshToken} {
+		api.esperar(api.requisitar(http.MethodPost, "/token/renovar", "", map[string]string{
+			"refreshToken": refreshToken,
+		}), http.StatusUnauthorized)
+	}
+
+	api.esperar(api.requisitar(http.MethodPost, "/login", "", map[string]string{
+		"email": "ana@devbook.test",
+		"senha": "Outra-senha-forte2",
+	}), http.StatusOK)
+}
@@ -0,0 +1,61 @@
+package router_test
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestVerificacaoDeEmail(t *testing.T) {
+	api := novaApi(t)
+	api.cadastrar("ana")
+
+	mensagens := api.caixa.aguardar(t, 1)
+	if mensagens[0].Para != "ana@devbook.test" {
+		t.Fatalf("email de verificação para %q", mensagens[0].Para)
+	}
+	token := tokenDoEmail(t, mensagens[0])
+
+	resposta := api.esperar(api.requisitar(http.MethodPost, "/usuarios/verificar-email", "", map[string]string{"token": "invalido"}), http.StatusBadRequest)
+	if codigo := resposta.codigo(t); codigo != "TOKEN_INVALIDO" {
+		t.Fatalf("código %q, esperado TOKEN_INVALIDO", codigo)
+	}
+
+	api.esperar(api.requisitar(http.MethodPost, "/usuarios/verificar-email", "", map[string]string{"token": token}), http.StatusNoContent)
+	resposta = api.esperar(api.requisitar(http.MethodPost, "/usuarios/verificar-email", "", map[string]string{"token": token}), http.StatusConflict)
+	if codigo := resposta.codigo(t); codigo != "EMAIL_JA_VERIFICADO" {
+		t.Fatalf("código %q, esperado EMAIL_JA_VERIFICADO", codigo)
+	}
+
+	usuario, erro := api.repositorios.Usuarios.BuscarPorEmail("ana@devbook.test")
+	if erro != nil {
+		t.Fatal(erro)
+	}
+	if !usuario.Verificado() {
+		t.Fatal("email não foi marcado como verificado")
+	}
+}
+
+func TestReenvioDeVerificacao(t *testing.T) {
+	api := novaApi(t)
+	api.cadastrar("ana")
+	api.cadastrar("bia")
+	// Os envios do cadastro são feitos em segundo plano e podem chegar em qualquer ordem
+	bia := api.caixa.aguardar(t, 2)[0]
+	if bia.Para != "bia@devbook.test" {
+		bia = api.caixa.aguardar(t, 2)[1]
+	}
+	api.esperar(api.requisitar(http.MethodPost, "/usuarios/verificar-email", "", map[string]string{"token": tokenDoEmail(t, bia)}), http.StatusNoContent)
+
+	// Email inexistente e email já verificado recebem a mesma resposta, sem envio
+	for _, email := range []string{"ninguem@devbook.test", "bia@devbook.test"} {
+		api.esperar(api.requisitar(http.MethodPost, "/usuarios/reenviar-verificacao", "", map[string]string{"email": email}), http.StatusAccepted)
+	}
+
+	api.esperar(api.requisitar(http.MethodPost, "/usuarios/reenviar-verificacao", "", map[string]string{"email": "ana@devbook.test"}), http.StatusAccepted)
+	mensagens := api.caixa.aguardar(t, 3)
+	if api.caixa.quantidade() != 3 || mensagens[2].Para != "ana@devbook.test" {
+		t.Fatalf("mensagens inesperadas: %+v", mensagens)
+	}
+
+	api.esperar(api.requisitar(http.MethodPost, "/usuarios/verificar-email", "", map[string]string{"token": tokenDoEmail(t, mensagens[2])}), http.StatusNoContent)
+}
@@ -0,0 +1,21 @@
+package saude
+
+import "sync/atomic"
+
+// pronto indica se a API pode receber tráfego. Começa falso e só é ligado depois que o servidor sobe.
+var pronto atomic.Bool
+
+// MarcarPronto sinaliza que a API está pronta para receber requisições
+func MarcarPronto() {
+	pronto.Store(true)
+}
+
+// MarcarEncerrando sinaliza que a API está encerrando e não deve mais receber tráfego novo
+func MarcarEncerrando() {
+	pronto.Store(false)
+}
+
+// Pronto indica se a API está pronta para receber requisições
+func Pronto() bool {
+	return pronto.Load()
+}
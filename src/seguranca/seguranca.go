@@ -1,6 +1,15 @@
 package seguranca
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	hashFicticio      []byte
+	gerarHashFicticio sync.Once
+)
 
 // Hash recebe uma senha e transforma num hash
 func Hash(senha string) ([]byte, error) {
@@ -10,4 +19,15 @@ func Hash(senha string) ([]byte, error) {
 // VerificarSenha valida se senha fornecida gera o mesmo hash da senha fornecida anteriormente
 func VerificarSenha(senhaHash, senhaString string) error {
 	return bcrypt.CompareHashAndPassword([]byte(senhaHash), []byte(senhaString))
-}
\ No newline at end of file
+}
+
+// SimularVerificacao executa uma comparação bcrypt contra um hash fictício, gastando o mesmo tempo
+// de VerificarSenha. É usada quando o email não existe, para que o tempo de resposta do login
+// não revele quais contas estão cadastradas.
+func SimularVerificacao(senhaString string) {
+	gerarHashFicticio.Do(func() {
+		hashFicticio, _ = bcrypt.GenerateFromPassword([]byte("senha-ficticia"), bcrypt.DefaultCost)
+	})
+
+	_ = bcrypt.CompareHashAndPassword(hashFicticio, []byte(senhaString))
+}
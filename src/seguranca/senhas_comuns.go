@@ -0,0 +1,33 @@
+package seguranca
+
+import (
+	_ "embed"
+	"strings"
+	"sync"
+)
+
+//go:embed senhas_comuns.txt
+var listaDeSenhasComuns string
+
+var (
+	senhasComuns         map[string]struct{}
+	carregarSenhasComuns sync.Once
+)
+
+// SenhaComum indica se a senha está na lista de senhas comuns embutida no binário.
+// A comparação não diferencia maiúsculas de minúsculas.
+func SenhaComum(senha string) bool {
+	carregarSenhasComuns.Do(func() {
+		senhasComuns = make(map[string]struct{})
+		for _, linha := range strings.Split(listaDeSenhasComuns, "\n") {
+			linha = strings.TrimSpace(linha)
+			if linha == "" || strings.HasPrefix(linha, "#") {
+				continue
+			}
+			senhasComuns[strings.ToLower(linha)] = struct{}{}
+		}
+	})
+
+	_, comum := senhasComuns[strings.ToLower(senha)]
+	return comum
+}
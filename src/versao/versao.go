@@ -0,0 +1,12 @@
+package versao
+
+// Commit e CompiladoEm são definidos na compilação, via ldflags:
+//
+//	go build -ldflags "-X api/src/versao.Commit=$(git rev-parse HEAD) -X api/src/versao.CompiladoEm=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	// Commit é o hash do commit git a partir do qual o binário foi compilado
+	Commit = "desconhecido"
+
+	// CompiladoEm é a data e hora (UTC, RFC 3339) da compilação
+	CompiladoEm = "desconhecido"
+)